@@ -0,0 +1,247 @@
+// Package filter 解析申请列表的高级筛选表达式。
+//
+// 表达式由空格分隔的若干条件组成，条件之间为"与"的关系，条件前加 - 表示取反：
+//
+//	status:interview,written      状态为其中之一
+//	tag:后端 tag:"机器 学习"         带有该标签（同一条件内多个标签为"或"）
+//	company:字节跳动,腾讯            公司为其中之一（按归一化后的名称比较）
+//	resume:v2                     使用的简历版本
+//	has:event / has:tag           有未完成的日程 / 有标签
+//	updated:-14d                  最近14天内更新过
+//	created:2024-03-01..2024-03-31 日期范围，两端包含，任一端可省略
+//	event:>=today event:<+7d      日程时间比较，支持 > >= < <=
+//	-status:rejected              取反
+//
+// 日期可以是 YYYY-MM-DD、today 或相对时间（-14d、+2w、-3m，单位为天、周、月）。
+// 单独的相对时间表示从现在到该时间之间。
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/pkg/utils"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 可筛选的字段
+const (
+	FieldStatus  = "status"
+	FieldTag     = "tag"
+	FieldCompany = "company"
+	FieldResume  = "resume"
+	FieldHas     = "has"
+	FieldCreated = "created"
+	FieldUpdated = "updated"
+	FieldEvent   = "event"
+)
+
+// has: 的取值
+const (
+	HasEvent = "event"
+	HasTag   = "tag"
+)
+
+// 表达式长度限制
+const (
+	maxExprLength = 1024
+	maxClauses    = 20
+)
+
+var ErrInvalidFilter = errors.New("无效的筛选条件")
+
+var relativePattern = regexp.MustCompile(`^([+-])(\d{1,4})([dwm])$`)
+
+// Clause 一个筛选条件
+type Clause struct {
+	Field  string
+	Negate bool
+	Values []string   // status、tag、company、has 的取值，多个取值之间为"或"
+	From   *time.Time // 日期下限（包含）
+	To     *time.Time // 日期上限（不包含）
+}
+
+// IsDate 是否为日期范围条件
+func (c Clause) IsDate() bool {
+	return c.Field == FieldCreated || c.Field == FieldUpdated || c.Field == FieldEvent
+}
+
+// Filter 解析后的筛选表达式
+type Filter struct {
+	Clauses []Clause
+}
+
+// Parse 解析筛选表达式，相对时间以 now 为基准
+func Parse(expr string, now time.Time) (Filter, error) {
+	var f Filter
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return f, nil
+	}
+	if len(expr) > maxExprLength {
+		return f, fmt.Errorf("%w: 表达式过长", ErrInvalidFilter)
+	}
+
+	for _, token := range utils.SplitQuoted(expr) {
+		clause := Clause{}
+		if strings.HasPrefix(token, "-") {
+			clause.Negate = true
+			token = token[1:]
+		}
+
+		i := strings.Index(token, ":")
+		if i <= 0 || i == len(token)-1 {
+			return f, fmt.Errorf("%w: %s 应为 字段:值 的形式", ErrInvalidFilter, token)
+		}
+		clause.Field = strings.ToLower(token[:i])
+		value := strings.ReplaceAll(token[i+1:], `"`, "")
+
+		switch clause.Field {
+		case FieldStatus, FieldTag, FieldCompany, FieldResume:
+			clause.Values = splitValues(value)
+		case FieldHas:
+			clause.Values = splitValues(value)
+			for _, v := range clause.Values {
+				if v != HasEvent && v != HasTag {
+					return f, fmt.Errorf("%w: has 只支持 event、tag", ErrInvalidFilter)
+				}
+			}
+		case FieldCreated, FieldUpdated, FieldEvent:
+			from, to, err := parseRange(value, now)
+			if err != nil {
+				return f, err
+			}
+			clause.From, clause.To = from, to
+		default:
+			return f, fmt.Errorf("%w: 未知字段 %s", ErrInvalidFilter, clause.Field)
+		}
+		if !clause.IsDate() && len(clause.Values) == 0 {
+			return f, fmt.Errorf("%w: %s 缺少取值", ErrInvalidFilter, clause.Field)
+		}
+
+		f.Clauses = append(f.Clauses, clause)
+	}
+
+	if len(f.Clauses) > maxClauses {
+		return f, fmt.Errorf("%w: 条件过多", ErrInvalidFilter)
+	}
+	return f, nil
+}
+
+// Validate 仅校验表达式语法，用于保存视图
+func Validate(expr string) error {
+	_, err := Parse(expr, time.Now())
+	return err
+}
+
+func splitValues(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseRange 解析日期范围，返回 [from, to)
+func parseRange(value string, now time.Time) (*time.Time, *time.Time, error) {
+	if a, b, ok := strings.Cut(value, ".."); ok {
+		var from, to *time.Time
+		if a != "" {
+			start, _, err := parsePoint(a, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			from = &start
+		}
+		if b != "" {
+			_, end, err := parsePoint(b, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			to = &end
+		}
+		if from == nil && to == nil {
+			return nil, nil, fmt.Errorf("%w: 日期范围为空", ErrInvalidFilter)
+		}
+		return from, to, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(value, op) {
+			continue
+		}
+		start, end, err := parsePoint(strings.TrimPrefix(value, op), now)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch op {
+		case ">=":
+			return &start, nil, nil
+		case ">":
+			return &end, nil, nil
+		case "<=":
+			return nil, &end, nil
+		default:
+			return nil, &start, nil
+		}
+	}
+
+	// 单独的相对时间：从现在到该时间之间
+	if relativePattern.MatchString(value) {
+		point, _, err := parsePoint(value, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if point.Before(now) {
+			return &point, nil, nil
+		}
+		return &now, &point, nil
+	}
+
+	// 单独的日期：当天
+	start, end, err := parsePoint(value, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &start, &end, nil
+}
+
+// parsePoint 解析一个时间点，返回其起止：日期为当天 [0点, 次日0点)，相对时间起止相同
+func parsePoint(value string, now time.Time) (time.Time, time.Time, error) {
+	if value == "today" {
+		start := startOfDay(now)
+		return start, start.AddDate(0, 0, 1), nil
+	}
+
+	if m := relativePattern.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		var t time.Time
+		switch m[3] {
+		case "d":
+			t = now.AddDate(0, 0, n)
+		case "w":
+			t = now.AddDate(0, 0, 7*n)
+		case "m":
+			t = now.AddDate(0, n, 0)
+		}
+		return t, t, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 无法识别的日期 %s", ErrInvalidFilter, value)
+	}
+	return day, day.AddDate(0, 0, 1), nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
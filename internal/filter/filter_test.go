@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describe 将条件格式化为便于比较的字符串，如 -status[rejected] 或 created[2026-03-01 00:00..]
+func describe(c Clause) string {
+	s := c.Field
+	if c.Negate {
+		s = "-" + s
+	}
+	if !c.IsDate() {
+		return s + "[" + strings.Join(c.Values, " ") + "]"
+	}
+	format := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+	return s + "[" + format(c.From) + ".." + format(c.To) + "]"
+}
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"status:interview,written", []string{"status[interview written]"}},
+		{"-status:rejected", []string{"-status[rejected]"}},
+		{`tag:"机器 学习" tag:后端`, []string{"tag[机器 学习]", "tag[后端]"}},
+		{"COMPANY:字节跳动,,腾讯", []string{"company[字节跳动 腾讯]"}},
+		{"resume:v2　has:event,tag", []string{"resume[v2]", "has[event tag]"}},
+		{"created:2026-03-10", []string{"created[2026-03-10 00:00..2026-03-11 00:00]"}},
+		{"created:2026-03-01..2026-03-31", []string{"created[2026-03-01 00:00..2026-04-01 00:00]"}},
+		{"created:2026-03-01..", []string{"created[2026-03-01 00:00..]"}},
+		{"created:..2026-03-31", []string{"created[..2026-04-01 00:00]"}},
+		{"created:-3m..today", []string{"created[2025-12-15 10:30..2026-03-16 00:00]"}},
+		{"updated:-14d", []string{"updated[2026-03-01 10:30..]"}},
+		{"-updated:-1w", []string{"-updated[2026-03-08 10:30..]"}},
+		{"event:+2w", []string{"event[2026-03-15 10:30..2026-03-29 10:30]"}},
+		{"event:>=today", []string{"event[2026-03-15 00:00..]"}},
+		{"event:>today", []string{"event[2026-03-16 00:00..]"}},
+		{"event:<=2026-03-20", []string{"event[..2026-03-21 00:00]"}},
+		{"event:<+7d", []string{"event[..2026-03-22 10:30]"}},
+		{"status:submitted -tag:内推 updated:-30d", []string{"status[submitted]", "-tag[内推]", "updated[2026-02-13 10:30..]"}},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expr, now)
+		if err != nil {
+			t.Errorf("Parse(%q) 出错: %v", c.expr, err)
+			continue
+		}
+		var got []string
+		for _, clause := range f.Clauses {
+			got = append(got, describe(clause))
+		}
+		if strings.Join(got, " | ") != strings.Join(c.want, " | ") {
+			t.Errorf("Parse(%q) = %q, 期望 %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	cases := []string{
+		"status",
+		":interview",
+		"status:",
+		"-",
+		"status:,",
+		"has:resume",
+		"unknown:x",
+		"created:..",
+		"created:2026-13-01",
+		"created:yesterday",
+		"created:2026-03-01..later",
+		"event:>=",
+		"event:>=+99999d",
+		"updated:-14x",
+		strings.Repeat("a", maxExprLength+1),
+		strings.TrimSpace(strings.Repeat("has:tag ", maxClauses+1)),
+	}
+	for _, expr := range cases {
+		if _, err := Parse(expr, now); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("Parse(%q) 期望 ErrInvalidFilter，实际 %v", expr, err)
+		}
+	}
+}
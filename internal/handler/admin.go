@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService *service.AdminService
+}
+
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{
+		adminService: &service.AdminService{},
+	}
+}
+
+// ListUsers 分页查询用户
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+
+	page, pageSize := parsePage(c)
+	search := c.DefaultQuery("search", "")
+
+	users, total, err := h.adminService.ListUsers(principalFromContext(c), page, pageSize, search)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":        users,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// DisableUser 禁用账号
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// EnableUser 启用账号
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *AdminHandler) setUserDisabled(c *gin.Context, disabled bool) {
+	targetID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.SetUserDisabled(principalFromContext(c), targetID, disabled); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+}
+
+// ResetPassword 为用户发起密码重置，重置链接发送到用户邮箱
+func (h *AdminHandler) ResetPassword(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.ResetPassword(principalFromContext(c), targetID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "重置链接已发送至用户邮箱"})
+}
+
+// GetStats 获取系统统计信息
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.adminService.GetSystemStats(principalFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"statistics": stats})
+}
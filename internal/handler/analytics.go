@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsHandler() *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: &service.AnalyticsService{},
+	}
+}
+
+// parseLocation 解析 tz 参数（IANA 时区名，如 Asia/Shanghai），未传时使用服务器时区
+func parseLocation(c *gin.Context) (*time.Location, bool) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return time.Local, true
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的时区"})
+		return nil, false
+	}
+	return loc, true
+}
+
+// parseAnalyticsFilter 解析 from、to（YYYY-MM-DD，两端包含）、tag、company、resume_version 筛选参数
+func parseAnalyticsFilter(c *gin.Context, loc *time.Location) (service.AnalyticsFilter, bool) {
+	f := service.AnalyticsFilter{
+		Tag:           c.Query("tag"),
+		Company:       c.Query("company"),
+		ResumeVersion: c.Query("resume_version"),
+	}
+
+	for key, dest := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的日期: " + key})
+			return f, false
+		}
+		if key == "to" {
+			day = day.AddDate(0, 0, 1)
+		}
+		*dest = &day
+	}
+	return f, true
+}
+
+// GetFunnel 投递漏斗：各阶段转化率、流失数和停留时长中位数
+func (h *AnalyticsHandler) GetFunnel(c *gin.Context) {
+	loc, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+	filter, ok := parseAnalyticsFilter(c, loc)
+	if !ok {
+		return
+	}
+
+	funnel, err := h.analyticsService.GetFunnel(principalFromContext(c), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"funnel": funnel})
+}
+
+// GetTimeseries 按日、周、月统计投递、回复、面试等数量，用于热力图和趋势图
+// metric 可用逗号分隔多个指标，interval 为 day、week 或 month，tz 为用户时区
+func (h *AnalyticsHandler) GetTimeseries(c *gin.Context) {
+	loc, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+	filter, ok := parseAnalyticsFilter(c, loc)
+	if !ok {
+		return
+	}
+
+	series, err := h.analyticsService.GetTimeseries(principalFromContext(c), service.TimeseriesQuery{
+		Metrics:  strings.Split(c.DefaultQuery("metric", service.MetricApplications), ","),
+		Interval: c.DefaultQuery("interval", service.IntervalDay),
+		Location: loc,
+		From:     filter.From,
+		To:       filter.To,
+		Filter:   filter,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"timeseries": series})
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"internship-manager/internal/model"
 	"internship-manager/internal/service"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,25 +14,31 @@ import (
 
 type ApplicationHandler struct {
 	applicationService *service.ApplicationService
+	viewService        *service.ViewService
+	staleService       *service.StaleService
+	goalService        *service.GoalService
 }
 
 func NewApplicationHandler() *ApplicationHandler {
 	return &ApplicationHandler{
 		applicationService: &service.ApplicationService{},
+		viewService:        &service.ViewService{},
+		staleService:       &service.StaleService{},
+		goalService:        &service.GoalService{},
 	}
 }
 
 // CreateApplication 创建实习申请
 func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
-	userID := c.GetUint("userID")
 	var req struct {
 		// 必填字段
 		Company  string `json:"company" binding:"required"`
 		Position string `json:"position" binding:"required"`
 
 		// 可选字段
-		EventLink string `json:"event_link"`
-		Notes     string `json:"notes"`
+		EventLink     string `json:"event_link"`
+		Notes         string `json:"notes"`
+		ResumeVersion string `json:"resume_version"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -44,24 +52,30 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 
 	// 创建申请记录
 	application := model.Application{
-		UserID:    userID,
-		Company:   req.Company,
-		Position:  req.Position,
-		Status:    model.StatusSubmitted,
-		EventLink: req.EventLink,
-		Notes:     req.Notes,
+		Company:       req.Company,
+		Position:      req.Position,
+		Status:        model.StatusSubmitted,
+		EventLink:     req.EventLink,
+		Notes:         req.Notes,
+		ResumeVersion: req.ResumeVersion,
 	}
 
-	err := h.applicationService.CreateApplicationFull(&application)
+	duplicates, err := h.applicationService.CreateApplicationFull(principalFromContext(c), &application)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "创建成功"})
+	resp := gin.H{"message": "创建成功", "application": application}
+	// 存在疑似重复的申请时提示用户，可通过合并接口处理
+	if len(duplicates) > 0 {
+		resp["warning"] = "可能与已有申请重复"
+		resp["duplicates"] = duplicates
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
-// UpdateStatus 更新申请状态
+// UpdateStatus 更新申请状态，支持 If-Match 乐观锁
 func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 	var req struct {
 		ID     uint                    `json:"id" binding:"required"`
@@ -73,30 +87,59 @@ func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	err := h.applicationService.UpdateApplicationStatus(req.ID, req.Status)
+	expectedVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.applicationService.UpdateApplicationStatus(principalFromContext(c), req.ID, req.Status, expectedVersion)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+	setETag(c, application.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "application": application})
 }
 
-// UpdateApplication 更新申请信息
+// GetApplication 获取单条申请详情，响应头携带 ETag
+func (h *ApplicationHandler) GetApplication(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	application, err := h.applicationService.GetApplication(principalFromContext(c), applicationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, application.Version)
+	c.JSON(http.StatusOK, gin.H{"application": application})
+}
+
+// UpdateApplication 更新申请信息，支持 If-Match 乐观锁
 func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
-	userID := c.GetUint("userID")
 	var req struct {
 		ID        uint   `json:"id" binding:"required"`
 		Company   string `json:"company" binding:"required"`
 		Position  string `json:"position" binding:"required"`
 		EventLink string `json:"event_link" binding:"required"`
 		Notes     string `json:"notes"`
+		// 未传时保持不变，兼容旧客户端
+		ResumeVersion *string `json:"resume_version" binding:"omitempty,max=64"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
 		return
 	}
+	// ETag 针对路径中的资源，请求体中的ID必须与之一致
+	if c.Param("id") != strconv.FormatUint(uint64(req.ID), 10) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID不一致"})
+		return
+	}
 	if req.Notes == "" {
 		req.Notes = "无"
 	}
@@ -108,14 +151,23 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 		"event_link": req.EventLink,
 		"notes":      req.Notes,
 	}
+	if req.ResumeVersion != nil {
+		updates["resume_version"] = *req.ResumeVersion
+	}
 
-	err := h.applicationService.UpdateApplication(req.ID, userID, updates)
+	expectedVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.applicationService.UpdateApplication(principalFromContext(c), req.ID, updates, expectedVersion)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+	setETag(c, application.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "application": application})
 }
 
 //// GetApplications 获取用户的申请（分页）
@@ -148,21 +200,66 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 //	})
 //}
 
-// GetApplications 获取用户的申请（分页）
+// 合并补丁请求体的大小上限，申请的可编辑字段远小于该值
+const maxPatchBodyBytes = 64 << 10
+
+// PatchApplication 按 JSON Merge Patch（RFC 7396）部分更新申请，支持 If-Match 乐观锁
+func (h *ApplicationHandler) PatchApplication(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != "application/merge-patch+json" && contentType != "application/json" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "请使用 application/merge-patch+json"})
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "请求数据过大"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据"})
+		return
+	}
+
+	expectedVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.applicationService.PatchApplication(principalFromContext(c), applicationID, patch, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, application.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "application": application})
+}
+
+// GetApplications 获取用户的申请
+// 筛选参数见 parseListQuery；传 limit 或 cursor 时按游标分页，
+// 支持 sort（updated_at、created_at、company、status、next_event）和 order（asc、desc）；
+// 否则按 page、pageSize 分页，兼容旧客户端
 func (h *ApplicationHandler) GetApplications(c *gin.Context) {
-	userID := c.GetUint("userID")
+	q, ok := h.parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("limit") != "" || c.Query("cursor") != "" {
+		h.listApplicationsByCursor(c, q)
+		return
+	}
 
 	// 获取分页参数
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	searchQuery := c.DefaultQuery("search", "")
-
-	// 获取状态筛选参数
-	statusesStr := c.DefaultQuery("statuses", "")
-	var statuses []string
-	if statusesStr != "" {
-		statuses = strings.Split(statusesStr, ",")
-	}
 
 	// 参数验证
 	if page < 1 {
@@ -171,27 +268,119 @@ func (h *ApplicationHandler) GetApplications(c *gin.Context) {
 	if pageSize < 1 {
 		pageSize = 10
 	}
+	if pageSize > service.MaxListLimit {
+		pageSize = service.MaxListLimit
+	}
 
-	applications, total, err := h.applicationService.GetApplicationsWithPagination(userID, page, pageSize, searchQuery, statuses)
+	applications, total, truncated, err := h.applicationService.GetApplicationsWithPagination(principalFromContext(c), page, pageSize, q.filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"applications": applications,
 		"total":        total,
+		"truncated":    truncated,
 		"current_page": page,
 		"page_size":    pageSize,
 	})
 }
 
+// listQuery 申请列表和统计共用的筛选参数
+type listQuery struct {
+	filter service.ApplicationFilter
+	sort   string
+	desc   *bool // 视图中保存的排序方向，为 nil 时使用默认方向
+	given  bool  // 请求是否带有任何筛选参数
+}
+
+// parseListQuery 解析筛选参数：search（全文检索）、filter（高级筛选表达式）、statuses、archived，
+// 以及 view（已保存视图的ID）。视图条件与请求中的 filter 同时生效，请求中的其余参数优先于视图
+func (h *ApplicationHandler) parseListQuery(c *gin.Context) (*listQuery, bool) {
+	q := &listQuery{sort: c.Query("sort")}
+	q.filter.Search = c.Query("search")
+	q.filter.Filter = c.Query("filter")
+	if statuses := c.Query("statuses"); statuses != "" {
+		q.filter.Statuses = strings.Split(statuses, ",")
+	}
+	q.filter.Archived = c.Query("archived") == "true"
+
+	if viewParam := c.Query("view"); viewParam != "" {
+		viewID, err := strconv.ParseUint(viewParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的视图ID"})
+			return nil, false
+		}
+		view, err := h.viewService.GetView(principalFromContext(c), uint(viewID))
+		if err != nil {
+			respondError(c, err)
+			return nil, false
+		}
+
+		q.filter.Filter = strings.TrimSpace(view.Filter + " " + q.filter.Filter)
+		if q.filter.Search == "" {
+			q.filter.Search = view.Search
+		}
+		if c.Query("archived") == "" {
+			q.filter.Archived = view.Archived
+		}
+		if q.sort == "" && view.Sort != "" {
+			q.sort = view.Sort
+			q.desc = &view.SortDesc
+		}
+	}
+
+	for _, key := range []string{"view", "search", "filter", "statuses", "archived"} {
+		if c.Query(key) != "" {
+			q.given = true
+		}
+	}
+	return q, true
+}
+
+// listApplicationsByCursor 游标分页，时间类排序默认倒序，其余默认正序
+func (h *ApplicationHandler) listApplicationsByCursor(c *gin.Context, q *listQuery) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultListLimit)))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的limit"})
+		return
+	}
+
+	desc := q.sort == "" || q.sort == service.SortUpdatedAt || q.sort == service.SortCreatedAt
+	if q.desc != nil {
+		desc = *q.desc
+	}
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order 只能为 asc 或 desc"})
+		return
+	}
+
+	page, err := h.applicationService.ListApplications(principalFromContext(c), service.ListOptions{
+		ApplicationFilter: q.filter,
+		Sort:              q.sort,
+		Desc:              desc,
+		Cursor:            c.Query("cursor"),
+		Limit:             limit,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
 // GetRecentApplications 获取用户最近的5条申请记录
 func (h *ApplicationHandler) GetRecentApplications(c *gin.Context) {
-	userID := c.GetUint("userID")
-
 	// 获取最近5条记录
-	applications, err := h.applicationService.GetRecentApplicationsByUserID(userID, 5)
+	applications, err := h.applicationService.GetRecentApplications(principalFromContext(c), 5)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -200,20 +389,36 @@ func (h *ApplicationHandler) GetRecentApplications(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"applications": applications})
 }
 
-// GetStatistics 获取申请统计信息
+// GetStatistics 获取申请统计信息，支持与列表相同的筛选参数
 func (h *ApplicationHandler) GetStatistics(c *gin.Context) {
-	userID := c.GetUint("userID")
-	stats, err := h.applicationService.GetApplicationStatistics(userID)
+	q, ok := h.parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	// 不带筛选参数时保持原有统计口径（包含已归档的申请）
+	var filter *service.ApplicationFilter
+	if q.given {
+		filter = &q.filter
+	}
+
+	p := principalFromContext(c)
+	stats, err := h.applicationService.GetApplicationStatistics(p, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+	// 目标进度与筛选条件无关，一并返回
+	goals, err := h.goalService.GetProgress(p)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"statistics": stats})
+	c.JSON(http.StatusOK, gin.H{"statistics": stats, "goals": goals})
 }
 
 // DeleteApplication 删除实习申请
 func (h *ApplicationHandler) DeleteApplication(c *gin.Context) {
-	userID := c.GetUint("userID")
 	id := c.Param("id")
 
 	// 将 id 转换为 uint
@@ -223,11 +428,146 @@ func (h *ApplicationHandler) DeleteApplication(c *gin.Context) {
 		return
 	}
 
-	err = h.applicationService.DeleteApplication(uint(applicationID), userID)
+	err = h.applicationService.DeleteApplication(principalFromContext(c), uint(applicationID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
+
+// GetTrash 获取回收站中的申请记录（分页）
+func (h *ApplicationHandler) GetTrash(c *gin.Context) {
+	page, pageSize := parsePage(c)
+
+	items, total, err := h.applicationService.ListTrash(principalFromContext(c), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applications": items,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// RestoreApplication 从回收站恢复申请
+func (h *ApplicationHandler) RestoreApplication(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.applicationService.RestoreApplication(principalFromContext(c), applicationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "恢复成功"})
+}
+
+// PurgeApplication 永久删除回收站中的申请
+func (h *ApplicationHandler) PurgeApplication(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.applicationService.PurgeApplication(principalFromContext(c), applicationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已永久删除"})
+}
+
+// EmptyTrash 清空回收站
+func (h *ApplicationHandler) EmptyTrash(c *gin.Context) {
+	count, err := h.applicationService.EmptyTrash(principalFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "回收站已清空", "purged": count})
+}
+
+// BulkUpdate 批量操作申请（修改状态、增删标签、删除、归档、恢复）
+func (h *ApplicationHandler) BulkUpdate(c *gin.Context) {
+	var req service.BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	results, err := h.applicationService.BulkUpdate(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Success {
+			succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+// GetHistory 获取申请的变更历史
+func (h *ApplicationHandler) GetHistory(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	history, err := h.applicationService.GetApplicationHistory(principalFromContext(c), applicationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// GetDuplicates 列出疑似重复的申请分组
+func (h *ApplicationHandler) GetDuplicates(c *gin.Context) {
+	groups, err := h.applicationService.ListDuplicateGroups(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// MergeApplications 将多条重复申请合并到一条保留的记录中
+func (h *ApplicationHandler) MergeApplications(c *gin.Context) {
+	var req struct {
+		TargetID  uint   `json:"target_id" binding:"required"`
+		SourceIDs []uint `json:"source_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	application, err := h.applicationService.MergeApplications(principalFromContext(c), req.TargetID, req.SourceIDs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, application.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "合并成功", "application": application})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{
+		auditService: &service.AuditService{},
+	}
+}
+
+// GetMyAuditEvents 获取当前用户自己的操作记录
+func (h *AuditHandler) GetMyAuditEvents(c *gin.Context) {
+	page, pageSize := parsePage(c)
+
+	events, total, err := h.auditService.ListUserEvents(principalFromContext(c), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":       events,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// GetAllAuditEvents 管理员查询全部审计日志
+// 支持 actor_id、owner_id、action、target_type、target_id、from、to（RFC3339）筛选
+func (h *AuditHandler) GetAllAuditEvents(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	q := service.AuditQuery{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+
+	for key, dest := range map[string]*uint{
+		"actor_id":  &q.ActorID,
+		"owner_id":  &q.OwnerID,
+		"target_id": &q.TargetID,
+	} {
+		if v := c.Query(key); v != "" {
+			id, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的" + key})
+				return
+			}
+			*dest = uint(id)
+		}
+	}
+
+	for key, dest := range map[string]**time.Time{
+		"from": &q.From,
+		"to":   &q.To,
+	} {
+		if v := c.Query(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的时间格式: " + key})
+				return
+			}
+			*dest = &t
+		}
+	}
+
+	events, total, err := h.auditService.ListAllEvents(principalFromContext(c), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":       events,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// parsePage 解析分页参数，pageSize 上限为 100
+func parsePage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
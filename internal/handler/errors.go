@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"internship-manager/internal/filter"
+	"internship-manager/internal/search"
+	"internship-manager/internal/service"
+	"internship-manager/pkg/webhook"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// respondError 根据业务错误类型返回对应的HTTP状态码
+func respondError(c *gin.Context, err error) {
+	// 版本冲突时返回最新数据，客户端据此合并后重试
+	var conflict *service.VersionConflictError
+	if errors.As(err, &conflict) {
+		setETag(c, conflict.Version)
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": conflict.Error(), "current": conflict.Current})
+		return
+	}
+
+	var validation *service.ValidationError
+	if errors.As(err, &validation) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": validation.Error(), "field": validation.Field})
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrApplicationNotFound), errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrExportNotFound), errors.Is(err, service.ErrExportExpired),
+		errors.Is(err, service.ErrEventNotFound), errors.Is(err, service.ErrViewNotFound),
+		errors.Is(err, service.ErrGoalNotFound), errors.Is(err, service.ErrNotificationNotFound),
+		errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrQuestionNotFound),
+		errors.Is(err, service.ErrExperienceNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrModifySelf), errors.Is(err, service.ErrEmailTokenInvalid),
+		errors.Is(err, service.ErrResetTokenInvalid),
+		errors.Is(err, service.ErrDeletionNotRequested), errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrInvalidBulkRequest),
+		errors.Is(err, service.ErrInvalidPatch), errors.Is(err, service.ErrInvalidMerge),
+		errors.Is(err, search.ErrInvalidQuery), errors.Is(err, service.ErrInvalidEvent),
+		errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidSort),
+		errors.Is(err, filter.ErrInvalidFilter), errors.Is(err, service.ErrInvalidView),
+		errors.Is(err, service.ErrViewLimit), errors.Is(err, service.ErrInvalidMetric),
+		errors.Is(err, service.ErrInvalidInterval), errors.Is(err, service.ErrInvalidRange),
+		errors.Is(err, service.ErrInvalidStaleRule), errors.Is(err, service.ErrInvalidWeek),
+		errors.Is(err, service.ErrInvalidSubscription), errors.Is(err, service.ErrInvalidNotificationSetting),
+		errors.Is(err, service.ErrInvalidGoal), errors.Is(err, service.ErrGoalLimit),
+		errors.Is(err, service.ErrInvalidTask), errors.Is(err, service.ErrInvalidDue),
+		errors.Is(err, service.ErrInvalidQuestion), errors.Is(err, service.ErrInvalidTopic),
+		errors.Is(err, service.ErrInvalidExperience), errors.Is(err, service.ErrOwnExperience),
+		errors.Is(err, service.ErrInvalidReport), errors.Is(err, service.ErrInvalidModeration),
+		errors.Is(err, service.ErrInvalidCompany),
+		errors.Is(err, webhook.ErrInvalidURL), errors.Is(err, webhook.ErrForbiddenAddress):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrWrongPassword):
+		status = http.StatusUnauthorized
+	case errors.Is(err, service.ErrEmailTaken), errors.Is(err, service.ErrExportRunning),
+		errors.Is(err, service.ErrViewNameTaken), errors.Is(err, service.ErrExperienceExists),
+		errors.Is(err, service.ErrAlreadyReported):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// bindStrictJSON 解析JSON请求体，出现未声明的字段时报错，并执行 binding 校验
+func bindStrictJSON(c *gin.Context, obj interface{}) error {
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// parseIDParam 解析路径中的ID参数
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
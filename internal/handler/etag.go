@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag 以版本号作为资源的 ETag
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// parseIfMatch 解析 If-Match 请求头中的版本号，未携带或为 * 时返回 nil
+func parseIfMatch(c *gin.Context) (*uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	value := strings.TrimPrefix(header, "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 If-Match"})
+		return nil, false
+	}
+
+	v := uint(version)
+	return &v, true
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 即将到来的日程默认查询天数及上限
+const (
+	defaultUpcomingDays = 7
+	maxUpcomingDays     = 90
+)
+
+// GetEvents 获取申请的笔试、面试等日程
+func (h *ApplicationHandler) GetEvents(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	events, err := h.applicationService.ListEvents(principalFromContext(c), applicationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// CreateEvent 为申请新增日程
+func (h *ApplicationHandler) CreateEvent(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.EventInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	event, err := h.applicationService.CreateEvent(principalFromContext(c), applicationID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "创建成功", "event": event})
+}
+
+// UpdateEvent 修改日程或标记完成
+func (h *ApplicationHandler) UpdateEvent(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	eventID, ok := parseIDParam(c, "eventId")
+	if !ok {
+		return
+	}
+
+	var req service.EventUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	event, err := h.applicationService.UpdateEvent(principalFromContext(c), applicationID, eventID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "event": event})
+}
+
+// DeleteEvent 删除日程
+func (h *ApplicationHandler) DeleteEvent(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	eventID, ok := parseIDParam(c, "eventId")
+	if !ok {
+		return
+	}
+
+	if err := h.applicationService.DeleteEvent(principalFromContext(c), applicationID, eventID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetUpcomingEvents 获取接下来几天内尚未完成的日程，days 默认7天
+func (h *ApplicationHandler) GetUpcomingEvents(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultUpcomingDays)))
+	if err != nil || days < 1 || days > maxUpcomingDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的天数"})
+		return
+	}
+
+	events, err := h.applicationService.ListUpcomingEvents(principalFromContext(c), time.Duration(days)*24*time.Hour)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// GetMockInterviews 获取不关联申请的模拟面试
+func (h *ApplicationHandler) GetMockInterviews(c *gin.Context) {
+	events, err := h.applicationService.ListMockInterviews(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// CreateMockInterview 记录不关联申请的模拟面试
+func (h *ApplicationHandler) CreateMockInterview(c *gin.Context) {
+	var req service.MockInterviewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	event, err := h.applicationService.CreateMockInterview(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "创建成功", "event": event})
+}
+
+// UpdateMockInterview 修改模拟面试或标记完成
+func (h *ApplicationHandler) UpdateMockInterview(c *gin.Context) {
+	eventID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.EventUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	event, err := h.applicationService.UpdateMockInterview(principalFromContext(c), eventID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "event": event})
+}
+
+// DeleteMockInterview 删除模拟面试
+func (h *ApplicationHandler) DeleteMockInterview(c *gin.Context) {
+	eventID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.applicationService.DeleteMockInterview(principalFromContext(c), eventID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
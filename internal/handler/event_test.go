@@ -0,0 +1,29 @@
+package handler_test
+
+import (
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"net/http"
+	"testing"
+)
+
+func TestDeleteEventKeepsQuestions(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	question := model.InterviewQuestion{UserID: f.owner.ID, ApplicationID: f.app.ID, EventID: &f.event.ID, Question: "讲讲你做过的项目"}
+	mustCreate(t, &question)
+
+	w := f.request(t, f.owner, http.MethodDelete, fmt.Sprintf("/api/applications/%d/events/%d", f.app.ID, f.event.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+
+	var got model.InterviewQuestion
+	if err := database.DB.First(&got, question.ID).Error; err != nil {
+		t.Fatalf("面试题被删除: %v", err)
+	}
+	if got.EventID != nil {
+		t.Fatalf("面试题仍关联已删除的日程 %d", *got.EventID)
+	}
+}
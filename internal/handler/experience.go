@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExperienceHandler struct {
+	experienceService *service.ExperienceService
+}
+
+func NewExperienceHandler() *ExperienceHandler {
+	return &ExperienceHandler{
+		experienceService: &service.ExperienceService{},
+	}
+}
+
+// GetExperiences 查询公开的面经，支持 company、position 筛选，sort 为 latest（默认）或 top
+func (h *ExperienceHandler) GetExperiences(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	experiences, total, err := h.experienceService.ListExperiences(principalFromContext(c), service.ExperienceQuery{
+		Company:  c.Query("company"),
+		Position: c.Query("position"),
+		Sort:     c.Query("sort"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"experiences":  experiences,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// GetMyExperiences 获取自己发布的面经
+func (h *ExperienceHandler) GetMyExperiences(c *gin.Context) {
+	experiences, err := h.experienceService.ListMyExperiences(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"experiences": experiences})
+}
+
+// GetDraft 根据 application_id 对应的申请生成面经草稿，修改后提交发布
+func (h *ExperienceHandler) GetDraft(c *gin.Context) {
+	applicationID, err := strconv.ParseUint(c.Query("application_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的application_id"})
+		return
+	}
+
+	draft, err := h.experienceService.DraftExperience(principalFromContext(c), uint(applicationID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"draft": draft})
+}
+
+// GetExperience 获取单篇面经
+func (h *ExperienceHandler) GetExperience(c *gin.Context) {
+	experienceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	experience, err := h.experienceService.GetExperience(principalFromContext(c), experienceID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"experience": experience})
+}
+
+// PublishExperience 发布面经
+func (h *ExperienceHandler) PublishExperience(c *gin.Context) {
+	var req service.ExperienceInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	experience, err := h.experienceService.PublishExperience(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "发布成功", "experience": experience})
+}
+
+// DeleteExperience 撤回自己发布的面经
+func (h *ExperienceHandler) DeleteExperience(c *gin.Context) {
+	experienceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.experienceService.DeleteExperience(principalFromContext(c), experienceID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// Upvote 点赞
+func (h *ExperienceHandler) Upvote(c *gin.Context) {
+	h.setUpvote(c, true)
+}
+
+// RemoveUpvote 取消点赞
+func (h *ExperienceHandler) RemoveUpvote(c *gin.Context) {
+	h.setUpvote(c, false)
+}
+
+func (h *ExperienceHandler) setUpvote(c *gin.Context, upvote bool) {
+	experienceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	experience, err := h.experienceService.SetUpvote(principalFromContext(c), experienceID, upvote)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"upvoted": upvote, "upvotes": experience.Upvotes})
+}
+
+// ReportExperience 举报面经
+func (h *ExperienceHandler) ReportExperience(c *gin.Context) {
+	experienceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	if err := h.experienceService.ReportExperience(principalFromContext(c), experienceID, req.Reason); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "举报成功"})
+}
+
+// GetReportedExperiences 管理员查看面经审核队列
+func (h *ExperienceHandler) GetReportedExperiences(c *gin.Context) {
+	page, pageSize := parsePage(c)
+
+	experiences, total, err := h.experienceService.ListReportedExperiences(principalFromContext(c), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"experiences":  experiences,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// ModerateExperience 管理员处理举报，action 为 approve 或 remove
+func (h *ExperienceHandler) ModerateExperience(c *gin.Context) {
+	experienceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Action string `json:"action" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	experience, err := h.experienceService.ModerateExperience(principalFromContext(c), experienceID, req.Action)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "处理成功", "experience": experience})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GoalHandler struct {
+	goalService *service.GoalService
+}
+
+func NewGoalHandler() *GoalHandler {
+	return &GoalHandler{
+		goalService: &service.GoalService{},
+	}
+}
+
+// GetGoals 获取当前用户的全部目标
+func (h *GoalHandler) GetGoals(c *gin.Context) {
+	goals, err := h.goalService.ListGoals(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"goals": goals})
+}
+
+// CreateGoal 新建目标
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	var req service.GoalInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	goal, err := h.goalService.CreateGoal(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "创建成功", "goal": goal})
+}
+
+// UpdateGoal 修改目标
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+	goalID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.GoalInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	goal, err := h.goalService.UpdateGoal(principalFromContext(c), goalID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "goal": goal})
+}
+
+// DeleteGoal 删除目标
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	goalID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.goalService.DeleteGoal(principalFromContext(c), goalID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetProgress 获取启用目标在当前周期的进度和连续达成次数
+func (h *GoalHandler) GetProgress(c *gin.Context) {
+	progress, err := h.goalService.GetProgress(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"progress": progress})
+}
@@ -0,0 +1,45 @@
+package handler_test
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/internal/service"
+	"internship-manager/pkg/database"
+	"testing"
+	"time"
+)
+
+func TestGoalNotificationsSkipInactiveUsers(t *testing.T) {
+	f := setupOwnershipFixture(t)
+	now := time.Now()
+
+	disabled := model.User{Username: "disabled", Password: "x", Email: "disabled@example.com", Role: model.RoleUser, Disabled: true}
+	mustCreate(t, &disabled)
+	leaving := model.User{Username: "leaving", Password: "x", Email: "leaving@example.com", Role: model.RoleUser, DeletionRequestedAt: &now}
+	mustCreate(t, &leaving)
+
+	goals := map[uint]*model.Goal{}
+	for _, user := range []model.User{f.owner, disabled, leaving} {
+		goal := &model.Goal{UserID: user.ID, Metric: model.GoalMockInterviews, Period: "week", Target: 1, Active: true}
+		mustCreate(t, goal)
+		goals[user.ID] = goal
+		mustCreate(t, &model.ApplicationEvent{UserID: user.ID, Type: model.EventMock, Title: "模拟面试", ScheduledAt: now, DoneAt: &now})
+	}
+
+	if err := (&service.GoalService{}).NotifyAchievedGoals(); err != nil {
+		t.Fatalf("发送目标通知失败: %v", err)
+	}
+
+	for userID, goal := range goals {
+		var got model.Goal
+		database.DB.First(&got, goal.ID)
+		if notified := got.LastAchievedPeriod != ""; notified != (userID == f.owner.ID) {
+			t.Errorf("用户 %d 的目标通知状态错误: %q", userID, got.LastAchievedPeriod)
+		}
+	}
+
+	var notifications int64
+	database.DB.Model(&model.Notification{}).Count(&notifications)
+	if notifications != 1 {
+		t.Fatalf("期望 1 条通知，实际 %d", notifications)
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InsightHandler struct {
+	insightService *service.InsightService
+}
+
+func NewInsightHandler() *InsightHandler {
+	return &InsightHandler{
+		insightService: &service.InsightService{},
+	}
+}
+
+// GetCompanies 查询可以查看统计的公司，q 为公司名关键词
+func (h *InsightHandler) GetCompanies(c *gin.Context) {
+	companies, err := h.insightService.ListCompanies(principalFromContext(c), c.Query("q"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"companies": companies})
+}
+
+// GetCompanyInsights 获取公司的回复时间、面试率等统计，id 为公司列表返回的 id 或公司名
+func (h *InsightHandler) GetCompanyInsights(c *gin.Context) {
+	insights, err := h.insightService.GetCompanyInsights(principalFromContext(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"insights": insights})
+}
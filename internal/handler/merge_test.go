@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMergeKeepsTargetTimeline(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	source := model.Application{UserID: f.owner.ID, Company: "示例科技", Position: "后端开发实习", Status: model.StatusInterview}
+	mustCreate(t, &source)
+	later := time.Now().Add(time.Hour)
+	mustCreate(t, &model.ApplicationHistory{ApplicationID: source.ID, UserID: f.owner.ID, Action: model.HistoryCreate, ToStatus: model.StatusSubmitted, CreatedAt: later})
+	mustCreate(t, &model.ApplicationHistory{ApplicationID: source.ID, UserID: f.owner.ID, Action: model.HistoryStatus,
+		FromStatus: model.StatusSubmitted, ToStatus: model.StatusInterview, CreatedAt: later.Add(time.Hour)})
+	experience := model.Experience{UserID: f.owner.ID, ApplicationID: &source.ID, Company: "示例科技", Position: "后端开发实习",
+		Outcome: model.OutcomeOffer, Rounds: model.ExperienceRounds{}, Status: model.ExperiencePublished}
+	mustCreate(t, &experience)
+
+	w := f.request(t, f.owner, http.MethodPost, "/api/applications/merge", gin.H{"target_id": f.app.ID, "source_ids": []uint{source.ID}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("合并期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+
+	var history []model.ApplicationHistory
+	database.DB.Where("application_id = ?", f.app.ID).Order("id ASC").Find(&history)
+	var creates, statuses int
+	var merge *model.ApplicationHistory
+	for i, row := range history {
+		switch row.Action {
+		case model.HistoryCreate:
+			creates++
+		case model.HistoryStatus:
+			statuses++
+		case model.HistoryMerge:
+			merge = &history[i]
+		}
+	}
+	if creates != 1 || statuses != 0 {
+		t.Fatalf("只应保留目标记录的时间线，实际新建 %d 条、状态变更 %d 条", creates, statuses)
+	}
+	if merge == nil || !strings.Contains(merge.Detail, "submitted→interview") {
+		t.Fatalf("合并历史应记录被合并记录的状态变化: %+v", merge)
+	}
+
+	var got model.Experience
+	database.DB.First(&got, experience.ID)
+	if got.ApplicationID == nil || *got.ApplicationID != f.app.ID {
+		t.Fatalf("面经应迁移到保留的记录: %v", got.ApplicationID)
+	}
+}
+
+func TestMergeRejectsMultipleExperiences(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	source := model.Application{UserID: f.owner.ID, Company: "示例科技", Position: "后端开发实习", Status: model.StatusSubmitted}
+	mustCreate(t, &source)
+	for _, id := range []uint{f.app.ID, source.ID} {
+		id := id
+		mustCreate(t, &model.Experience{UserID: f.owner.ID, ApplicationID: &id, Company: "示例科技", Position: "后端实习",
+			Outcome: model.OutcomeOffer, Rounds: model.ExperienceRounds{}, Status: model.ExperiencePublished})
+	}
+
+	w := f.request(t, f.owner, http.MethodPost, "/api/applications/merge", gin.H{"target_id": f.app.ID, "source_ids": []uint{source.ID}})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("两条申请都有面经时期望 400，实际 %d: %s", w.Code, w.Body.String())
+	}
+	var count int64
+	database.DB.Model(&model.Application{}).Where("id = ?", source.ID).Count(&count)
+	if count != 1 {
+		t.Fatal("合并失败时不应删除被合并的记录")
+	}
+}
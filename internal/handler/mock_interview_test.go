@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMockInterviewWithoutApplication(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	w := f.request(t, f.owner, http.MethodPost, "/api/goals", gin.H{"metric": "mock_interviews", "period": "week", "target": 1})
+	if w.Code != http.StatusOK {
+		t.Fatalf("新建目标期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+
+	w = f.request(t, f.owner, http.MethodPost, "/api/mock-interviews", gin.H{"title": "系统设计练习", "scheduled_at": time.Now()})
+	if w.Code != http.StatusOK {
+		t.Fatalf("记录模拟面试期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Event model.ApplicationEvent `json:"event"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if created.Event.ApplicationID != nil || created.Event.Type != model.EventMock {
+		t.Fatalf("模拟面试不应关联申请: %+v", created.Event)
+	}
+	path := fmt.Sprintf("/api/mock-interviews/%d", created.Event.ID)
+
+	// 其他用户不能修改，也不能通过申请下的日程路径访问
+	if w := f.request(t, f.other, http.MethodPatch, path, gin.H{"done": true}); w.Code != http.StatusNotFound {
+		t.Fatalf("其他用户修改期望 404，实际 %d: %s", w.Code, w.Body.String())
+	}
+	if w := f.request(t, f.owner, http.MethodPatch, fmt.Sprintf("/api/applications/%d/events/%d", f.app.ID, created.Event.ID), gin.H{"done": true}); w.Code != http.StatusNotFound {
+		t.Fatalf("通过申请路径修改期望 404，实际 %d: %s", w.Code, w.Body.String())
+	}
+	if w := f.request(t, f.owner, http.MethodPatch, path, gin.H{"type": "interview"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("改为其他类型期望 400，实际 %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := f.request(t, f.owner, http.MethodPatch, path, gin.H{"done": true}); w.Code != http.StatusOK {
+		t.Fatalf("标记完成期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+
+	w = f.request(t, f.owner, http.MethodGet, "/api/goals/progress", nil)
+	var progress struct {
+		Progress []struct {
+			Current  int  `json:"current"`
+			Achieved bool `json:"achieved"`
+		} `json:"progress"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &progress); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if len(progress.Progress) != 1 || progress.Progress[0].Current != 1 || !progress.Progress[0].Achieved {
+		t.Fatalf("模拟面试应计入目标: %s", w.Body.String())
+	}
+
+	if w := f.request(t, f.owner, http.MethodDelete, path, nil); w.Code != http.StatusOK {
+		t.Fatalf("删除期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+	var count int64
+	database.DB.Model(&model.ApplicationEvent{}).Where("id = ?", created.Event.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("模拟面试没有被删除")
+	}
+}
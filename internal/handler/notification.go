@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler() *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: &service.NotificationService{},
+	}
+}
+
+// GetNotifications 获取最近的通知，unread=true 时只返回未读的
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	notifications, unread, err := h.notificationService.ListNotifications(principalFromContext(c), c.Query("unread") == "true")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread": unread})
+}
+
+// MarkRead 标记通知为已读
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	notificationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(principalFromContext(c), notificationID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已读"})
+}
+
+// MarkAllRead 标记全部通知为已读
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	if err := h.notificationService.MarkAllRead(principalFromContext(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已读"})
+}
+
+// GetSetting 查看通知推送方式
+func (h *NotificationHandler) GetSetting(c *gin.Context) {
+	setting, err := h.notificationService.GetSetting(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"setting": setting})
+}
+
+// UpdateSetting 修改通知推送方式
+func (h *NotificationHandler) UpdateSetting(c *gin.Context) {
+	var req service.NotificationSettingInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	setting, err := h.notificationService.UpdateSetting(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "setting": setting})
+}
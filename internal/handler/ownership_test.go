@@ -0,0 +1,240 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"internship-manager/internal/middleware"
+	"internship-manager/internal/model"
+	"internship-manager/internal/router"
+	"internship-manager/pkg/database"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ownershipFixture 用户A的各类记录，用户B以自己的身份逐一访问
+type ownershipFixture struct {
+	engine   *gin.Engine
+	owner    model.User
+	other    model.User
+	app      model.Application
+	trashed  model.Application
+	otherApp model.Application
+	event    model.ApplicationEvent
+	tag      model.Tag
+	view     model.SavedView
+}
+
+func setupOwnershipFixture(t *testing.T) *ownershipFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	middleware.InitJWT("test-secret")
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&model.User{}, &model.Application{}, &model.Tag{}, &model.ApplicationTag{},
+		&model.ApplicationHistory{}, &model.ApplicationEvent{}, &model.InterviewQuestion{},
+		&model.SavedView{}, &model.AuditEvent{}, &model.Goal{}, &model.Task{}, &model.Notification{},
+		&model.DigestSubscription{}, &model.NotificationSetting{}, &model.Experience{}, &model.StaleRule{},
+	); err != nil {
+		t.Fatalf("建表失败: %v", err)
+	}
+	database.DB = db
+
+	f := &ownershipFixture{engine: router.SetupRouter()}
+	f.owner = model.User{Username: "owner", Password: "x", Email: "owner@example.com", Role: model.RoleUser}
+	f.other = model.User{Username: "other", Password: "x", Email: "other@example.com", Role: model.RoleUser}
+	mustCreate(t, &f.owner)
+	mustCreate(t, &f.other)
+
+	f.tag = model.Tag{UserID: f.owner.ID, Name: "内推"}
+	mustCreate(t, &f.tag)
+	f.app = model.Application{UserID: f.owner.ID, Company: "示例科技", Position: "后端实习", Status: model.StatusSubmitted, Tags: []model.Tag{f.tag}}
+	mustCreate(t, &f.app)
+	f.trashed = model.Application{UserID: f.owner.ID, Company: "示例科技", Position: "前端实习", Status: model.StatusSubmitted}
+	mustCreate(t, &f.trashed)
+	if err := db.Delete(&f.trashed).Error; err != nil {
+		t.Fatalf("删除申请失败: %v", err)
+	}
+	f.otherApp = model.Application{UserID: f.other.ID, Company: "另一家公司", Position: "测试实习", Status: model.StatusSubmitted}
+	mustCreate(t, &f.otherApp)
+
+	mustCreate(t, &model.ApplicationHistory{ApplicationID: f.app.ID, UserID: f.owner.ID, Action: model.HistoryCreate, ToStatus: model.StatusSubmitted})
+	f.event = model.ApplicationEvent{ApplicationID: &f.app.ID, UserID: f.owner.ID, Type: model.EventInterview, Title: "一面", ScheduledAt: time.Now().Add(24 * time.Hour)}
+	mustCreate(t, &f.event)
+	token := "owner-share-token"
+	f.view = model.SavedView{UserID: f.owner.ID, Name: "我的视图", ShareToken: &token}
+	mustCreate(t, &f.view)
+	return f
+}
+
+func mustCreate(t *testing.T, value interface{}) {
+	t.Helper()
+	if err := database.DB.Create(value).Error; err != nil {
+		t.Fatalf("准备测试数据失败: %v", err)
+	}
+}
+
+// request 以 user 的身份发起请求
+func (f *ownershipFixture) request(t *testing.T, user model.User, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	token, err := middleware.GenerateToken(user.ID, 1)
+	if err != nil {
+		t.Fatalf("生成token失败: %v", err)
+	}
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("编码请求体失败: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	f.engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestOtherUserGetsNotFound(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	app := fmt.Sprintf("/api/applications/%d", f.app.ID)
+	trashed := fmt.Sprintf("/api/applications/%d", f.trashed.ID)
+	event := fmt.Sprintf("%s/events/%d", app, f.event.ID)
+	// 用户A的日程挂到用户B自己的申请路径下同样不能访问
+	eventViaOwnApp := fmt.Sprintf("/api/applications/%d/events/%d", f.otherApp.ID, f.event.ID)
+	view := fmt.Sprintf("/api/views/%d", f.view.ID)
+
+	// 用户A本人可以正常访问，排除路由或数据本身的问题
+	for _, path := range []string{app, app + "/history", app + "/events", app + "/questions"} {
+		if w := f.request(t, f.owner, http.MethodGet, path, nil); w.Code != http.StatusOK {
+			t.Fatalf("用户A访问 %s 期望 200，实际 %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	cases := []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{http.MethodGet, app, nil},
+		{http.MethodPut, app, gin.H{"id": f.app.ID, "company": "改名", "position": "改名", "event_link": "https://example.com"}},
+		{http.MethodPatch, app, gin.H{"notes": "改写"}},
+		{http.MethodDelete, app, nil},
+		{http.MethodPatch, "/api/applications/status", gin.H{"id": f.app.ID, "status": "rejected"}},
+		{http.MethodPost, trashed + "/restore", nil},
+		{http.MethodDelete, trashed + "/purge", nil},
+		{http.MethodGet, app + "/history", nil},
+		{http.MethodGet, app + "/questions", nil},
+		{http.MethodGet, app + "/events", nil},
+		{http.MethodPost, app + "/events", gin.H{"type": "interview", "title": "二面", "scheduled_at": time.Now().Add(48 * time.Hour)}},
+		{http.MethodPatch, event, gin.H{"title": "改写"}},
+		{http.MethodDelete, event, nil},
+		{http.MethodPatch, eventViaOwnApp, gin.H{"title": "改写"}},
+		{http.MethodDelete, eventViaOwnApp, nil},
+		{http.MethodPost, "/api/applications/merge", gin.H{"target_id": f.otherApp.ID, "source_ids": []uint{f.app.ID}}},
+		{http.MethodPut, view, gin.H{"name": "改名"}},
+		{http.MethodDelete, view, nil},
+		{http.MethodPost, view + "/share", nil},
+		{http.MethodDelete, view + "/share", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			w := f.request(t, f.other, tc.method, tc.path, tc.body)
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("期望 404，实际 %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// 以上请求都不能改动用户A的数据
+	var app2 model.Application
+	if err := database.DB.Preload("Tags").First(&app2, f.app.ID).Error; err != nil {
+		t.Fatalf("用户A的申请被删除: %v", err)
+	}
+	if app2.Company != f.app.Company || app2.Status != f.app.Status || app2.Notes != "" {
+		t.Fatalf("用户A的申请被修改: %+v", app2)
+	}
+	var events int64
+	database.DB.Model(&model.ApplicationEvent{}).Where("application_id = ?", f.app.ID).Count(&events)
+	if events != 1 {
+		t.Fatalf("用户A的日程数量变为 %d", events)
+	}
+	var view2 model.SavedView
+	if err := database.DB.First(&view2, f.view.ID).Error; err != nil || view2.Name != f.view.Name || view2.ShareToken == nil {
+		t.Fatalf("用户A的视图被修改: %+v, %v", view2, err)
+	}
+	var trashedCount int64
+	database.DB.Unscoped().Model(&model.Application{}).Where("id = ? AND deleted_at IS NOT NULL", f.trashed.ID).Count(&trashedCount)
+	if trashedCount != 1 {
+		t.Fatal("用户A回收站中的申请被恢复或永久删除")
+	}
+}
+
+func TestOtherUserCannotTagOrUntag(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	for _, action := range []string{"add_tag", "remove_tag"} {
+		w := f.request(t, f.other, http.MethodPost, "/api/applications/bulk",
+			gin.H{"ids": []uint{f.app.ID}, "action": action, "tag": f.tag.Name})
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: 期望 200，实际 %d: %s", action, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Results []struct {
+				ID      uint   `json:"id"`
+				Success bool   `json:"success"`
+				Error   string `json:"error"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: 解析响应失败: %v", action, err)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].Success {
+			t.Fatalf("%s: 期望操作别人的申请失败，实际 %s", action, w.Body.String())
+		}
+	}
+
+	var tags []model.Tag
+	if err := database.DB.Model(&f.app).Association("Tags").Find(&tags); err != nil {
+		t.Fatalf("查询标签失败: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != f.tag.ID {
+		t.Fatalf("用户A的申请标签被修改: %+v", tags)
+	}
+
+	// 标签列表只包含自己的标签
+	w := f.request(t, f.other, http.MethodGet, "/api/tags", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(f.tag.Name)) {
+		t.Fatalf("标签列表包含用户A的标签: %s", w.Body.String())
+	}
+}
+
+func TestOtherUserViewsAreScoped(t *testing.T) {
+	f := setupOwnershipFixture(t)
+
+	w := f.request(t, f.other, http.MethodGet, "/api/views", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200，实际 %d: %s", w.Code, w.Body.String())
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(f.view.Name)) {
+		t.Fatalf("视图列表包含用户A的视图: %s", w.Body.String())
+	}
+}
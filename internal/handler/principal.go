@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalFromContext 从请求上下文中构造当前操作者
+func principalFromContext(c *gin.Context) service.Principal {
+	return service.Principal{
+		UserID:    c.GetUint("userID"),
+		Role:      c.GetString("role"),
+		IP:        c.ClientIP(),
+		RequestID: c.GetString("requestID"),
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionHandler struct {
+	questionService *service.QuestionService
+}
+
+func NewQuestionHandler() *QuestionHandler {
+	return &QuestionHandler{
+		questionService: &service.QuestionService{},
+	}
+}
+
+// GetQuestions 在全部申请中检索面试题
+// 支持 company（公司名模糊匹配）、topic、q（题目、回答、复盘关键词）、application_id、event_id 筛选
+func (h *QuestionHandler) GetQuestions(c *gin.Context) {
+	page, pageSize := parsePage(c)
+	q := service.QuestionQuery{
+		Company:  c.Query("company"),
+		Topic:    model.QuestionTopic(c.Query("topic")),
+		Keyword:  c.Query("q"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	for key, dest := range map[string]**uint{
+		"application_id": &q.ApplicationID,
+		"event_id":       &q.EventID,
+	} {
+		if v := c.Query(key); v != "" {
+			id, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的" + key})
+				return
+			}
+			value := uint(id)
+			*dest = &value
+		}
+	}
+
+	questions, total, err := h.questionService.ListQuestions(principalFromContext(c), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"questions":    questions,
+		"total":        total,
+		"current_page": page,
+		"page_size":    pageSize,
+	})
+}
+
+// GetApplicationQuestions 获取某条申请各轮面试的题目
+func (h *QuestionHandler) GetApplicationQuestions(c *gin.Context) {
+	applicationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	questions, err := h.questionService.ListApplicationQuestions(principalFromContext(c), applicationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"questions": questions})
+}
+
+// CreateQuestion 记录面试题
+func (h *QuestionHandler) CreateQuestion(c *gin.Context) {
+	var req service.QuestionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	question, err := h.questionService.CreateQuestion(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "创建成功", "question": question})
+}
+
+// UpdateQuestion 修改面试题
+func (h *QuestionHandler) UpdateQuestion(c *gin.Context) {
+	questionID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.QuestionUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	question, err := h.questionService.UpdateQuestion(principalFromContext(c), questionID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "question": question})
+}
+
+// DeleteQuestion 删除面试题
+func (h *QuestionHandler) DeleteQuestion(c *gin.Context) {
+	questionID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.questionService.DeleteQuestion(principalFromContext(c), questionID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler() *ReportHandler {
+	return &ReportHandler{
+		reportService: &service.ReportService{},
+	}
+}
+
+// GetWeeklyReport 获取周报，week 为 ISO 周（2026-W42）或该周内任意一天，默认本周；
+// format=text 时返回与邮件相同的纯文本
+func (h *ReportHandler) GetWeeklyReport(c *gin.Context) {
+	loc, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+	start, err := service.ParseWeek(c.Query("week"), loc)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	p := principalFromContext(c)
+	report, err := h.reportService.GetWeeklyReport(p, start)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if c.Query("format") == "text" {
+		user, err := service.NewUserService().GetUserByID(p, p.UserID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		text, err := service.RenderWeeklyReport(user.Username, report)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.String(http.StatusOK, text)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// GetSummaryPDF 下载求职总结 PDF，tz 为时区
+func (h *ReportHandler) GetSummaryPDF(c *gin.Context) {
+	loc, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.reportService.SummaryPDF(principalFromContext(c), loc)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="summary.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GetSubscription 获取周报订阅设置
+func (h *ReportHandler) GetSubscription(c *gin.Context) {
+	sub, err := h.reportService.GetSubscription(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+// UpdateSubscription 修改周报订阅设置
+func (h *ReportHandler) UpdateSubscription(c *gin.Context) {
+	var req service.SubscriptionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	sub, err := h.reportService.UpdateSubscription(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "subscription": sub})
+}
@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"internship-manager/internal/search"
+	"internship-manager/internal/service"
+	"internship-manager/pkg/database"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// 内嵌检索后端搭配非 MySQL 数据库时，按页码和按游标分页的搜索都可用，且只返回本人的申请
+func TestMemorySearchOnSQLite(t *testing.T) {
+	f := setupOwnershipFixture(t)
+	service.InitSearch(search.NewMemoryBackend(database.DB))
+
+	for _, path := range []string{
+		"/api/applications?search=" + url.QueryEscape("示例"),
+		"/api/applications?limit=10&search=" + url.QueryEscape("示例"),
+	} {
+		w := f.request(t, f.owner, http.MethodGet, path, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: 期望 200，实际 %d: %s", path, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Applications []struct {
+				ID uint `json:"id"`
+			} `json:"applications"`
+			Truncated bool `json:"truncated"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: 解析响应失败: %v", path, err)
+		}
+		if len(resp.Applications) != 1 || resp.Applications[0].ID != f.app.ID || resp.Truncated {
+			t.Fatalf("%s: 期望只命中未删除的申请 %d，实际 %s", path, f.app.ID, w.Body.String())
+		}
+
+		w = f.request(t, f.other, http.MethodGet, path, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: 期望 200，实际 %d: %s", path, w.Code, w.Body.String())
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Applications) != 0 {
+			t.Fatalf("%s: 不应搜索到别人的申请: %s", path, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStaleApplications 按用户的停滞规则列出长期没有进展的申请
+func (h *ApplicationHandler) GetStaleApplications(c *gin.Context) {
+	list, err := h.staleService.ListStale(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetStaleRule 获取停滞规则，未设置时返回默认规则
+func (h *ApplicationHandler) GetStaleRule(c *gin.Context) {
+	rule, err := h.staleService.GetRule(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rule": rule})
+}
+
+// UpdateStaleRule 修改停滞规则
+func (h *ApplicationHandler) UpdateStaleRule(c *gin.Context) {
+	var req service.StaleRuleInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	rule, err := h.staleService.UpdateRule(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "rule": rule})
+}
@@ -0,0 +1,46 @@
+package handler_test
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/internal/service"
+	"internship-manager/pkg/database"
+	"testing"
+	"time"
+)
+
+func TestStaleRulesSkipInactiveUsers(t *testing.T) {
+	f := setupOwnershipFixture(t)
+	now := time.Now()
+
+	disabled := model.User{Username: "disabled", Password: "x", Email: "disabled@example.com", Role: model.RoleUser, Disabled: true}
+	mustCreate(t, &disabled)
+	leaving := model.User{Username: "leaving", Password: "x", Email: "leaving@example.com", Role: model.RoleUser, DeletionRequestedAt: &now}
+	mustCreate(t, &leaving)
+
+	stale := now.AddDate(0, 0, -60)
+	apps := map[uint]*model.Application{}
+	for _, user := range []model.User{f.owner, disabled, leaving} {
+		mustCreate(t, &model.StaleRule{UserID: user.ID, StaleDays: 30, Statuses: model.StatusList{model.StatusSubmitted},
+			Action: model.StaleActionGhosted, ActionDays: 45})
+		app := &model.Application{UserID: user.ID, Company: "停滞公司", Position: "实习", Status: model.StatusSubmitted}
+		mustCreate(t, app)
+		database.DB.Model(app).UpdateColumn("updated_at", stale)
+		apps[user.ID] = app
+	}
+
+	if err := (&service.StaleService{}).ApplyStaleRules(); err != nil {
+		t.Fatalf("处理停滞规则失败: %v", err)
+	}
+
+	for userID, app := range apps {
+		var got model.Application
+		database.DB.First(&got, app.ID)
+		want := model.StatusSubmitted
+		if userID == f.owner.ID {
+			want = model.StatusGhosted
+		}
+		if got.Status != want {
+			t.Errorf("用户 %d 的申请状态为 %s，期望 %s", userID, got.Status, want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService *service.TagService
+}
+
+func NewTagHandler() *TagHandler {
+	return &TagHandler{
+		tagService: &service.TagService{},
+	}
+}
+
+// GetTags 获取当前用户的全部标签
+func (h *TagHandler) GetTags(c *gin.Context) {
+	tags, err := h.tagService.ListTags(principalFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
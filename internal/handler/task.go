@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskHandler struct {
+	taskService *service.TaskService
+}
+
+func NewTaskHandler() *TaskHandler {
+	return &TaskHandler{
+		taskService: &service.TaskService{},
+	}
+}
+
+// GetTasks 获取待办任务
+// due 为 today、overdue 或 week；completed 为 true、false（默认）或 all；可按 application_id 筛选
+func (h *TaskHandler) GetTasks(c *gin.Context) {
+	loc, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+	q := service.TaskQuery{Due: c.Query("due"), Location: loc}
+
+	if value := c.Query("application_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+			return
+		}
+		applicationID := uint(id)
+		q.ApplicationID = &applicationID
+	}
+
+	switch c.DefaultQuery("completed", "false") {
+	case "all":
+	case "true":
+		completed := true
+		q.Completed = &completed
+	case "false":
+		completed := false
+		q.Completed = &completed
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 completed 参数"})
+		return
+	}
+
+	tasks, err := h.taskService.ListTasks(principalFromContext(c), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
+
+// CreateTask 新建任务
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	var req service.TaskInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	task, err := h.taskService.CreateTask(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "创建成功", "task": task})
+}
+
+// UpdateTask 修改任务，`{"completed": true}` 标记完成
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.TaskUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	task, err := h.taskService.UpdateTask(principalFromContext(c), taskID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "task": task})
+}
+
+// DeleteTask 删除任务
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.taskService.DeleteTask(principalFromContext(c), taskID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
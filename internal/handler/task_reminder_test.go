@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/internal/service"
+	"internship-manager/pkg/database"
+	"testing"
+	"time"
+)
+
+func TestTaskRemindersSkipTrashedAndInactive(t *testing.T) {
+	f := setupOwnershipFixture(t)
+	due := time.Now().Add(10 * time.Minute)
+
+	disabled := model.User{Username: "disabled", Password: "x", Email: "disabled@example.com", Role: model.RoleUser, Disabled: true}
+	mustCreate(t, &disabled)
+	now := time.Now()
+	leaving := model.User{Username: "leaving", Password: "x", Email: "leaving@example.com", Role: model.RoleUser, DeletionRequestedAt: &now}
+	mustCreate(t, &leaving)
+
+	remind := []*model.Task{
+		{UserID: f.owner.ID, Title: "关联申请", ApplicationID: &f.app.ID, DueAt: &due},
+		{UserID: f.owner.ID, Title: "不关联申请", DueAt: &due},
+	}
+	skip := []*model.Task{
+		{UserID: f.owner.ID, Title: "回收站中的申请", ApplicationID: &f.trashed.ID, DueAt: &due},
+		{UserID: disabled.ID, Title: "被禁用的用户", DueAt: &due},
+		{UserID: leaving.ID, Title: "申请注销的用户", DueAt: &due},
+	}
+	for _, task := range append(append([]*model.Task{}, remind...), skip...) {
+		mustCreate(t, task)
+	}
+
+	if err := (&service.TaskService{}).SendTaskReminders(); err != nil {
+		t.Fatalf("发送提醒失败: %v", err)
+	}
+
+	for _, task := range remind {
+		var got model.Task
+		database.DB.First(&got, task.ID)
+		if got.RemindedAt == nil {
+			t.Errorf("任务「%s」应发送提醒", task.Title)
+		}
+	}
+	for _, task := range skip {
+		var got model.Task
+		database.DB.First(&got, task.ID)
+		if got.RemindedAt != nil {
+			t.Errorf("任务「%s」不应发送提醒", task.Title)
+		}
+	}
+
+	var notifications int64
+	database.DB.Model(&model.Notification{}).Count(&notifications)
+	if notifications != int64(len(remind)) {
+		t.Fatalf("期望 %d 条通知，实际 %d", len(remind), notifications)
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"internship-manager/internal/middleware"
@@ -32,7 +33,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	err := h.userService.Register(req.Username, req.Password, req.Email)
+	err := h.userService.Register(principalFromContext(c), req.Username, req.Password, req.Email)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -72,9 +73,11 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"token": token,
 		"user": gin.H{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
+			"id":                    user.ID,
+			"username":              user.Username,
+			"email":                 user.Email,
+			"role":                  user.Role,
+			"deletion_scheduled_at": user.DeletionScheduledAt,
 		},
 	})
 }
@@ -82,30 +85,102 @@ func (h *UserHandler) Login(c *gin.Context) {
 // GetProfile 获取用户个人信息
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := c.GetUint("userID")
-	user, err := h.userService.GetUserByID(userID)
+	user, err := h.userService.GetUserByID(principalFromContext(c), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取用户信息失败"})
 		return
 	}
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
 // UpdateProfile 更新用户个人信息
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
-	userID := c.GetUint("userID")
-	var userData map[string]interface{}
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// 只接受 ProfileUpdate 中声明的字段，邮箱等其它字段一律拒绝
+	var req service.ProfileUpdate
+	if err := bindStrictJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据", "details": err.Error()})
+		return
+	}
+
+	expectedVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(principalFromContext(c), userID, req, expectedVersion)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "user": user})
+}
+
+// RequestEmailChange 申请修改邮箱，验证链接发送到新邮箱
+func (h *UserHandler) RequestEmailChange(c *gin.Context) {
+	var req struct {
+		NewEmail string `json:"new_email" binding:"required,email"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
 
-	if err := c.ShouldBindJSON(&userData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据"})
+	if err := h.userService.RequestEmailChange(principalFromContext(c), req.NewEmail, req.Password); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "验证邮件已发送至新邮箱"})
+}
+
+// ConfirmEmailChange 通过邮件中的令牌确认新邮箱
+func (h *UserHandler) ConfirmEmailChange(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	user, err := h.userService.ConfirmEmailChange(principalFromContext(c), req.Token)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "邮箱已更新", "user": user})
+}
+
+// ConfirmPasswordReset 通过邮件中的令牌设置新密码
+func (h *UserHandler) ConfirmPasswordReset(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required,min=6"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
 		return
 	}
 
-	if err := h.userService.UpdateUser(userID, userData); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.ConfirmPasswordReset(principalFromContext(c), req.Token, req.NewPassword); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "更新成功"})
+	c.JSON(http.StatusOK, gin.H{"message": "密码已重置"})
 }
 
 //// UpdatePassword 更新密码
@@ -129,14 +204,95 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 //	c.JSON(http.StatusOK, gin.H{"message": "密码更新成功"})
 //}
 
-// DeleteAccount 删除账号
+// DeleteAccount 申请注销账号，需密码确认，冷静期内可撤销
 func (h *UserHandler) DeleteAccount(c *gin.Context) {
-	userID := c.GetUint("userID")
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请输入密码确认注销"})
+		return
+	}
+
+	user, err := h.userService.RequestDeletion(principalFromContext(c), userID, req.Password)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":               "已申请注销，冷静期结束后账号及全部数据将被清除",
+		"deletion_scheduled_at": user.DeletionScheduledAt,
+		"export_url":            "/api/user/export",
+	})
+}
+
+// CancelDeletion 撤销注销
+func (h *UserHandler) CancelDeletion(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.userService.CancelDeletion(principalFromContext(c), userID); err != nil {
+		respondError(c, err)
+		return
+	}
 
-	if err := h.userService.DeleteUser(userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, gin.H{"message": "已撤销注销"})
+}
+
+// ExportData 导出个人全部数据（JSON）
+func (h *UserHandler) ExportData(c *gin.Context) {
+	export, err := h.userService.ExportUserData(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="export.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// StartExport 创建异步数据导出任务（ZIP）
+func (h *UserHandler) StartExport(c *gin.Context) {
+	job, err := h.userService.StartExport(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "导出任务已创建", "job": job})
+}
+
+// GetExport 查询导出任务状态，下载链接只通过邮件发送
+func (h *UserHandler) GetExport(c *gin.Context) {
+	jobID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.userService.GetExport(principalFromContext(c), jobID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// DownloadExport 通过限时令牌下载导出文件，无需登录
+func (h *UserHandler) DownloadExport(c *gin.Context) {
+	job, err := h.userService.OpenExportDownload(c.Param("token"))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "账号已删除"})
+	c.FileAttachment(job.FilePath, fmt.Sprintf("export-%s.zip", job.CreatedAt.Format("20060102")))
 }
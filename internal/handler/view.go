@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"internship-manager/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ViewHandler struct {
+	viewService *service.ViewService
+}
+
+func NewViewHandler() *ViewHandler {
+	return &ViewHandler{
+		viewService: &service.ViewService{},
+	}
+}
+
+// GetViews 获取当前用户保存的视图，置顶的在前
+func (h *ViewHandler) GetViews(c *gin.Context) {
+	views, err := h.viewService.ListViews(principalFromContext(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"views": views})
+}
+
+// CreateView 保存视图
+func (h *ViewHandler) CreateView(c *gin.Context) {
+	var req service.ViewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	view, err := h.viewService.CreateView(principalFromContext(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "创建成功", "view": view})
+}
+
+// UpdateView 修改视图
+func (h *ViewHandler) UpdateView(c *gin.Context) {
+	viewID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.ViewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	view, err := h.viewService.UpdateView(principalFromContext(c), viewID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "更新成功", "view": view})
+}
+
+// DeleteView 删除视图
+func (h *ViewHandler) DeleteView(c *gin.Context) {
+	viewID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.viewService.DeleteView(principalFromContext(c), viewID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// ShareView 分享视图，返回分享令牌
+func (h *ViewHandler) ShareView(c *gin.Context) {
+	viewID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	view, err := h.viewService.ShareView(principalFromContext(c), viewID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "分享成功", "view": view})
+}
+
+// UnshareView 取消分享
+func (h *ViewHandler) UnshareView(c *gin.Context) {
+	viewID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	view, err := h.viewService.UnshareView(principalFromContext(c), viewID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已取消分享", "view": view})
+}
+
+// GetSharedView 通过分享令牌查看视图定义
+func (h *ViewHandler) GetSharedView(c *gin.Context) {
+	view, err := h.viewService.GetSharedView(c.Param("token"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"view": view})
+}
+
+// CopySharedView 将分享的视图保存到自己的视图中，可通过 name 重命名
+func (h *ViewHandler) CopySharedView(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+
+	view, err := h.viewService.CopySharedView(principalFromContext(c), c.Param("token"), req.Name)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "保存成功", "view": view})
+}
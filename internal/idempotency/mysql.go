@@ -0,0 +1,84 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"internship-manager/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLStore 基于 MySQL 的幂等键存储
+type MySQLStore struct {
+	DB *gorm.DB
+}
+
+func NewMySQLStore(db *gorm.DB) *MySQLStore {
+	return &MySQLStore{DB: db}
+}
+
+func (s *MySQLStore) Reserve(ctx context.Context, userID uint, key, requestHash, owner string) (*Record, error) {
+	now := time.Now()
+	db := s.DB.WithContext(ctx)
+
+	// 过期的记录视为不存在
+	if err := db.Where("user_id = ? AND idem_key = ? AND expires_at <= ?", userID, key, now).
+		Delete(&model.IdempotencyRecord{}).Error; err != nil {
+		return nil, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.IdempotencyRecord{
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ReservationTTL),
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		Owner:       owner,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil, nil
+	}
+
+	var existing model.IdempotencyRecord
+	if err := db.Where("user_id = ? AND idem_key = ?", userID, key).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInProgress
+		}
+		return nil, err
+	}
+	return &Record{
+		RequestHash: existing.RequestHash,
+		Owner:       existing.Owner,
+		Completed:   existing.Completed,
+		StatusCode:  existing.StatusCode,
+		ContentType: existing.ContentType,
+		Body:        existing.Body,
+	}, nil
+}
+
+func (s *MySQLStore) Complete(ctx context.Context, userID uint, key string, record *Record) error {
+	return s.DB.WithContext(ctx).Model(&model.IdempotencyRecord{}).
+		Where("user_id = ? AND idem_key = ?", userID, key).
+		Updates(map[string]interface{}{
+			"completed":    true,
+			"expires_at":   time.Now().Add(TTL),
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"body":         record.Body,
+		}).Error
+}
+
+func (s *MySQLStore) Release(ctx context.Context, userID uint, key, owner string) error {
+	return s.DB.WithContext(ctx).Where("user_id = ? AND idem_key = ? AND owner = ? AND completed = ?", userID, key, owner, false).
+		Delete(&model.IdempotencyRecord{}).Error
+}
+
+// Cleanup 删除过期的幂等键，由后台任务调用
+func (s *MySQLStore) Cleanup() error {
+	return s.DB.Where("expires_at <= ?", time.Now()).Delete(&model.IdempotencyRecord{}).Error
+}
@@ -0,0 +1,76 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore 基于 Redis 的幂等键存储，过期由 Redis TTL 处理
+type RedisStore struct {
+	Client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{Client: client}
+}
+
+// releaseScript 只在键仍是 owner 未完成的占用时删除
+var releaseScript = redis.NewScript(`
+local data = redis.call("GET", KEYS[1])
+if not data then
+	return 0
+end
+local record = cjson.decode(data)
+if record.Completed or record.Owner ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+func redisKey(userID uint, key string) string {
+	return fmt.Sprintf("idempotency:%d:%s", userID, key)
+}
+
+func (s *RedisStore) Reserve(ctx context.Context, userID uint, key, requestHash, owner string) (*Record, error) {
+	data, err := json.Marshal(&Record{RequestHash: requestHash, Owner: owner})
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.Client.SetNX(ctx, redisKey(userID, key), data, ReservationTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+
+	existing, err := s.Client.Get(ctx, redisKey(userID, key)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(existing, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, userID uint, key string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.Client.Set(ctx, redisKey(userID, key), data, TTL).Err()
+}
+
+func (s *RedisStore) Release(ctx context.Context, userID uint, key, owner string) error {
+	return releaseScript.Run(ctx, s.Client, []string{redisKey(userID, key)}, owner).Err()
+}
@@ -0,0 +1,38 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// TTL 已完成请求的幂等键保存时长
+	TTL = 24 * time.Hour
+	// ReservationTTL 处理中的占用保存时长，请求异常中断未能释放时，过期后客户端即可重试
+	ReservationTTL = time.Minute
+)
+
+// ErrInProgress 相同幂等键的首次请求仍在处理中
+var ErrInProgress = errors.New("相同 Idempotency-Key 的请求正在处理中")
+
+// Record 幂等键对应的首次请求及其响应
+type Record struct {
+	RequestHash string
+	Owner       string // 占用幂等键的请求，释放时只删除自己的占用
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Store 幂等键存储
+type Store interface {
+	// Reserve 以 owner 的身份占用幂等键；首次占用返回 nil, nil，已存在时返回已有记录
+	Reserve(ctx context.Context, userID uint, key, requestHash, owner string) (*Record, error)
+	// Complete 保存首次请求的响应
+	Complete(ctx context.Context, userID uint, key string, record *Record) error
+	// Release 释放 owner 仍未完成的占用，用于首次请求失败后允许客户端重试。
+	// 占用已过期并被其他请求重新占用或完成时不做任何修改
+	Release(ctx context.Context, userID uint, key, owner string) error
+}
@@ -0,0 +1,37 @@
+package job
+
+import (
+	"internship-manager/internal/idempotency"
+	"internship-manager/internal/service"
+	"internship-manager/pkg/database"
+	"time"
+)
+
+// RegisterDefaults 注册系统内置的后台任务
+func RegisterDefaults() {
+	userService := service.NewUserService()
+	applicationService := &service.ApplicationService{}
+	staleService := &service.StaleService{}
+	reportService := &service.ReportService{}
+	goalService := &service.GoalService{}
+	taskService := &service.TaskService{}
+
+	// 清除注销冷静期已结束的账号
+	Register("purge-deleted-accounts", time.Hour, userService.PurgeDeletedAccounts)
+	// 清理过期的数据导出文件
+	Register("cleanup-exports", 30*time.Minute, userService.CleanupExports)
+	// 永久删除超过保留期的回收站记录
+	Register("purge-trash", time.Hour, applicationService.PurgeExpiredTrash)
+	// 为历史申请补全归一化公司名，用于查重
+	Register("backfill-company-keys", time.Hour, applicationService.BackfillCompanyKeys)
+	// 按用户规则自动处理长期无进展的申请
+	Register("apply-stale-rules", time.Hour, staleService.ApplyStaleRules)
+	// 每周一发送上一周的周报
+	Register("send-weekly-digests", time.Hour, reportService.SendWeeklyDigests)
+	// 目标达成通知
+	Register("notify-achieved-goals", 15*time.Minute, goalService.NotifyAchievedGoals)
+	// 待办任务到期提醒
+	Register("send-task-reminders", 5*time.Minute, taskService.SendTaskReminders)
+	// 删除 MySQL 中过期的幂等键（使用 Redis 存储时表为空，任务不做任何事）
+	Register("cleanup-idempotency-keys", time.Hour, idempotency.NewMySQLStore(database.DB).Cleanup)
+}
@@ -0,0 +1,50 @@
+package job
+
+import (
+	"log"
+	"time"
+)
+
+// Job 周期执行的后台任务
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+var jobs []Job
+
+// Register 注册后台任务，需在 Start 之前调用
+func Register(name string, interval time.Duration, run func() error) {
+	jobs = append(jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+// Start 启动所有已注册的后台任务，每个任务在独立的 goroutine 中按间隔执行
+func Start() {
+	for _, j := range jobs {
+		go loop(j)
+	}
+}
+
+func loop(j Job) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(j)
+		<-ticker.C
+	}
+}
+
+// runOnce 执行一次任务，panic 不会影响其它任务
+func runOnce(j Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[job] %s panic: %v", j.Name, r)
+		}
+	}()
+
+	if err := j.Run(); err != nil {
+		log.Printf("[job] %s failed: %v", j.Name, err)
+	}
+}
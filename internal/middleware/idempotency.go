@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"internship-manager/internal/idempotency"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+var idempotencyStore idempotency.Store
+
+// InitIdempotency 设置幂等键存储
+func InitIdempotency(store idempotency.Store) {
+	idempotencyStore = store
+}
+
+// responseRecorder 在写出响应的同时保留一份副本
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// Idempotency 幂等键中间件，需在 JWTAuth 之后使用。
+// 同一用户使用相同的 Idempotency-Key 重试时直接重放首次响应，请求体不同则返回 422
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || idempotencyStore == nil {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key 过长"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求数据"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.Path+"\n"), body...))
+		requestHash := hex.EncodeToString(sum[:])
+		userID := c.GetUint("userID")
+		ctx := c.Request.Context()
+
+		// 每个请求使用自己的占用标识，占用过期后迟到的释放不会删除其他请求的记录
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		owner := hex.EncodeToString(b)
+
+		existing, err := idempotencyStore.Reserve(ctx, userID, key, requestHash, owner)
+		if errors.Is(err, idempotency.ErrInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key 已用于不同的请求"})
+			case !existing.Completed:
+				c.JSON(http.StatusConflict, gin.H{"error": idempotency.ErrInProgress.Error()})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// 服务端错误不保存，允许客户端使用同一个键重试
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := idempotencyStore.Release(ctx, userID, key, owner); err != nil {
+				log.Printf("释放幂等键失败: %v", err)
+			}
+			return
+		}
+
+		if err := idempotencyStore.Complete(ctx, userID, key, &idempotency.Record{
+			RequestHash: requestHash,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}); err != nil {
+			log.Printf("保存幂等键响应失败: %v", err)
+		}
+	}
+}
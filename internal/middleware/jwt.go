@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
 	"net/http"
 	"strings"
 	"time"
@@ -63,7 +65,21 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 每次请求都确认账号仍然有效，保证禁用和角色变更即时生效
+		var user model.User
+		if err := database.DB.Select("id", "role", "disabled").First(&user, claims.UserID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "用户不存在"})
+			c.Abort()
+			return
+		}
+		if user.Disabled {
+			c.JSON(http.StatusForbidden, gin.H{"error": "账号已被禁用"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
+		c.Set("role", user.Role)
 		c.Next()
 	}
 }
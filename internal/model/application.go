@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -13,8 +15,18 @@ const (
 	StatusInterview ApplicationStatus = "interview" // 面试中
 	StatusAccepted  ApplicationStatus = "accepted"  // 已录用
 	StatusRejected  ApplicationStatus = "rejected"  // 已拒绝
+	StatusGhosted   ApplicationStatus = "ghosted"   // 长期无回复，视为石沉大海
 )
 
+// Valid 是否为合法的申请状态
+func (s ApplicationStatus) Valid() bool {
+	switch s {
+	case StatusSubmitted, StatusWritten, StatusInterview, StatusAccepted, StatusRejected, StatusGhosted:
+		return true
+	}
+	return false
+}
+
 // Application 实习申请记录
 type Application struct {
 	gorm.Model
@@ -25,6 +37,18 @@ type Application struct {
 	EventLink string            `json:"event_link"` // 链接
 	Notes     string            `gorm:"type:text" json:"notes"`
 
+	ResumeVersion string `gorm:"type:varchar(64)" json:"resume_version"` // 投递时使用的简历版本，用于对比不同简历的效果
+
+	CompanyKey string `gorm:"type:varchar(128);not null;default:''" json:"-"` // 归一化后的公司名，用于查重
+	StatusRank int    `gorm:"->" json:"-"`                                    // 状态在流程中的先后顺序，数据库生成列，用于排序
+
+	Version     uint       `gorm:"not null;default:1" json:"version"`                 // 乐观锁版本号，每次修改递增
+	ArchivedAt  *time.Time `gorm:"index" json:"archived_at"`                          // 归档时间，归档后默认不在列表中显示
+	NextEventAt *time.Time `json:"next_event_at"`                                     // 最近一个未完成日程的时间，由日程变更时维护
+	Tags        []Tag      `gorm:"many2many:application_tags;" json:"tags,omitempty"` // 标签
+
+	Highlights map[string]string `gorm:"-" json:"highlights,omitempty"` // 搜索结果中各字段的高亮摘要
+
 	//NextEvent   *time.Time `json:"next_event"` // 下一个面试/笔试时间
 	//EventType   string     `json:"event_type"` // 事件类型（笔试/面试）
 	//ApplyDate   time.Time  `json:"apply_date"`
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计操作类型
+const (
+	AuditActionUserRegister       = "user.register"        // 注册账号
+	AuditActionUserUpdate         = "user.update"          // 更新个人信息
+	AuditActionUserDelete         = "user.delete"          // 申请注销账号
+	AuditActionUserDeletionCancel = "user.deletion_cancel" // 撤销注销
+	AuditActionUserPurge          = "user.purge"           // 冷静期结束后清除账号数据
+	AuditActionUserExport         = "user.export"          // 申请导出个人数据
+
+	AuditActionUserEmailChangeRequest = "user.email_change_request" // 申请修改邮箱
+	AuditActionUserEmailChange        = "user.email_change"         // 新邮箱验证通过
+	AuditActionUserPasswordReset      = "user.password_reset"       // 通过重置链接设置新密码
+	AuditActionStaleRuleUpdate        = "user.stale_rule_update"    // 修改停滞申请规则
+	AuditActionDigestUpdate           = "user.digest_update"        // 修改周报订阅
+	AuditActionNotificationUpdate     = "user.notification_update"  // 修改通知推送方式
+
+	AuditActionApplicationCreate       = "application.create"        // 新增申请
+	AuditActionApplicationUpdate       = "application.update"        // 修改申请
+	AuditActionApplicationUpdateStatus = "application.update_status" // 修改申请状态
+	AuditActionApplicationDelete       = "application.delete"        // 删除申请（移入回收站）
+	AuditActionApplicationRestore      = "application.restore"       // 从回收站恢复
+	AuditActionApplicationPurge        = "application.purge"         // 永久删除
+	AuditActionApplicationArchive      = "application.archive"       // 归档
+	AuditActionApplicationUnarchive    = "application.unarchive"     // 取消归档
+	AuditActionApplicationTagAdd       = "application.tag_add"       // 添加标签
+	AuditActionApplicationTagRemove    = "application.tag_remove"    // 移除标签
+	AuditActionApplicationMerge        = "application.merge"         // 合并重复申请
+	AuditActionEventCreate             = "application.event_create"  // 新增日程
+	AuditActionEventUpdate             = "application.event_update"  // 修改日程
+	AuditActionEventDelete             = "application.event_delete"  // 删除日程
+
+	AuditActionViewCreate = "view.create" // 新建视图
+	AuditActionViewUpdate = "view.update" // 修改视图（含分享、取消分享）
+	AuditActionViewDelete = "view.delete" // 删除视图
+
+	AuditActionGoalCreate = "goal.create" // 新建目标
+	AuditActionGoalUpdate = "goal.update" // 修改目标
+	AuditActionGoalDelete = "goal.delete" // 删除目标
+
+	AuditActionTaskCreate = "task.create" // 新建任务（含自动创建）
+	AuditActionTaskUpdate = "task.update" // 修改任务
+	AuditActionTaskDelete = "task.delete" // 删除任务
+
+	AuditActionQuestionCreate = "question.create" // 记录面试题
+	AuditActionQuestionUpdate = "question.update" // 修改面试题
+	AuditActionQuestionDelete = "question.delete" // 删除面试题
+
+	AuditActionExperiencePublish = "experience.publish" // 发布面经
+	AuditActionExperienceDelete  = "experience.delete"  // 撤回面经
+	AuditActionExperienceReport  = "experience.report"  // 举报面经
+
+	AuditActionAdminListUsers     = "admin.user.list"           // 管理员查询用户列表
+	AuditActionAdminDisableUser   = "admin.user.disable"        // 管理员禁用账号
+	AuditActionAdminEnableUser    = "admin.user.enable"         // 管理员启用账号
+	AuditActionAdminResetPassword = "admin.user.reset_password" // 管理员重置密码
+	AuditActionAdminViewStats     = "admin.stats.view"          // 管理员查看系统统计
+	AuditActionAdminViewAudit     = "admin.audit.view"          // 管理员查询审计日志
+	AuditActionAdminViewReports   = "admin.experience.reports"  // 管理员查看面经审核队列
+	AuditActionAdminModerate      = "admin.experience.moderate" // 管理员审核被举报的面经
+)
+
+// 审计对象类型
+const (
+	AuditTargetUser        = "user"
+	AuditTargetApplication = "application"
+	AuditTargetEvent       = "event"
+	AuditTargetView        = "view"
+	AuditTargetGoal        = "goal"
+	AuditTargetTask        = "task"
+	AuditTargetQuestion    = "question"
+	AuditTargetExperience  = "experience"
+	AuditTargetSystem      = "system"
+)
+
+// AuditEvent 审计日志，只追加不修改
+type AuditEvent struct {
+	ID         uint            `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+	ActorID    uint            `gorm:"not null;index" json:"actor_id"` // 操作者
+	OwnerID    uint            `gorm:"index" json:"owner_id"`          // 被操作数据的所属用户
+	Action     string          `gorm:"type:varchar(64);not null" json:"action"`
+	TargetType string          `gorm:"type:varchar(32);not null" json:"target_type"`
+	TargetID   uint            `json:"target_id"`
+	Before     json.RawMessage `gorm:"column:before_data;type:json" json:"before,omitempty"` // 变更前的字段（仅包含变化部分）
+	After      json.RawMessage `gorm:"column:after_data;type:json" json:"after,omitempty"`   // 变更后的字段（仅包含变化部分）
+	IP         string          `gorm:"type:varchar(64)" json:"ip"`
+	RequestID  string          `gorm:"type:varchar(64)" json:"request_id"`
+	RedactedAt *time.Time      `json:"redacted_at,omitempty"` // 账号清除后变更内容和 IP 被清空的时间
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// EmailChangeRequest 修改邮箱申请，新邮箱验证通过后才会生效
+type EmailChangeRequest struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	NewEmail   string     `gorm:"type:varchar(128);not null" json:"new_email"`
+	TokenHash  string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+}
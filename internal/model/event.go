@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// EventType 申请相关的日程类型
+type EventType string
+
+const (
+	EventWritten   EventType = "written"   // 笔试
+	EventInterview EventType = "interview" // 面试
+	EventOther     EventType = "other"     // 其他（宣讲会、HR沟通等）
+	EventMock      EventType = "mock"      // 模拟面试
+)
+
+// Valid 是否为合法的事件类型
+func (t EventType) Valid() bool {
+	switch t {
+	case EventWritten, EventInterview, EventOther, EventMock:
+		return true
+	}
+	return false
+}
+
+// ApplicationEvent 申请的笔试、面试等日程
+type ApplicationEvent struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ApplicationID *uint      `gorm:"index" json:"application_id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	Type          EventType  `gorm:"type:varchar(16);not null" json:"type"`
+	Title         string     `gorm:"type:varchar(128)" json:"title"`
+	ScheduledAt   time.Time  `gorm:"not null" json:"scheduled_at"`
+	Link          string     `gorm:"type:varchar(256)" json:"link,omitempty"` // 会议链接、笔试地址等
+	Notes         string     `gorm:"type:text" json:"notes,omitempty"`
+	DoneAt        *time.Time `json:"done_at"` // 完成时间，为空表示尚未进行
+}
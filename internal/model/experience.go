@@ -0,0 +1,99 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// 面经的结果
+const (
+	OutcomeOffer      = "offer"       // 拿到 offer
+	OutcomeRejected   = "rejected"    // 未通过
+	OutcomeNoResponse = "no_response" // 无回复
+	OutcomeInProgress = "in_progress" // 流程中
+)
+
+// 面经的状态
+const (
+	ExperiencePublished = "published" // 公开显示
+	ExperienceHidden    = "hidden"    // 被举报次数过多，等待管理员审核
+	ExperienceRemoved   = "removed"   // 管理员审核后下架
+)
+
+// ExperienceRound 面经中的一轮笔试或面试
+type ExperienceRound struct {
+	Name      string   `json:"name"` // 如“一面”“笔试”
+	Type      string   `json:"type"` // written、interview、other
+	Month     string   `json:"month,omitempty"`
+	Questions []string `json:"questions,omitempty"`
+	Notes     string   `json:"notes,omitempty"`
+}
+
+// ExperienceRounds 各轮次，在数据库中以 JSON 保存
+type ExperienceRounds []ExperienceRound
+
+// Value 实现 driver.Valuer
+func (r ExperienceRounds) Value() (driver.Value, error) {
+	if r == nil {
+		r = ExperienceRounds{}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 sql.Scanner
+func (r *ExperienceRounds) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*r = nil
+		return nil
+	default:
+		return fmt.Errorf("无法将 %T 转换为 ExperienceRounds", value)
+	}
+	return json.Unmarshal(data, r)
+}
+
+// Experience 匿名分享的面经。作者和来源申请只用于去重、删除和账号清除，不在接口中返回
+type Experience struct {
+	ID            uint             `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	UserID        uint             `gorm:"not null;index" json:"-"`
+	ApplicationID *uint            `gorm:"uniqueIndex" json:"-"` // 每条申请只能发布一篇，申请永久删除后置空
+	Company       string           `gorm:"type:varchar(128);not null" json:"company"`
+	CompanyKey    string           `gorm:"type:varchar(128);not null;index" json:"-"` // 归一化后的公司名，用于搜索
+	Position      string           `gorm:"type:varchar(128);not null" json:"position"`
+	Outcome       string           `gorm:"type:varchar(16);not null" json:"outcome"`
+	Rounds        ExperienceRounds `gorm:"type:json;not null" json:"rounds"`
+	Summary       string           `gorm:"type:text" json:"summary"`
+	Status        string           `gorm:"type:varchar(16);not null;default:'published';index" json:"status"`
+	Upvotes       int              `gorm:"not null;default:0" json:"upvotes"`
+	ReportCount   int              `gorm:"not null;default:0" json:"-"` // 未处理的举报数，只对管理员显示
+}
+
+// ExperienceVote 用户对面经的点赞，每人每篇一次
+type ExperienceVote struct {
+	ExperienceID uint      `gorm:"primaryKey" json:"experience_id"`
+	UserID       uint      `gorm:"primaryKey;index" json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ExperienceReport 用户对面经的举报，每人每篇一次，管理员处理后记录处理时间
+type ExperienceReport struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExperienceID uint       `gorm:"not null;uniqueIndex:idx_experience_reports_user" json:"experience_id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_experience_reports_user;index" json:"-"`
+	Reason       string     `gorm:"type:varchar(512);not null" json:"reason"`
+	ResolvedAt   *time.Time `json:"resolved_at"`
+}
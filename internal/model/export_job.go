@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// ExportJobStatus 导出任务状态
+type ExportJobStatus string
+
+const (
+	ExportPending ExportJobStatus = "pending" // 排队中
+	ExportRunning ExportJobStatus = "running" // 生成中
+	ExportDone    ExportJobStatus = "done"    // 已完成
+	ExportFailed  ExportJobStatus = "failed"  // 失败
+	ExportExpired ExportJobStatus = "expired" // 下载链接已过期
+)
+
+// ExportJob 个人数据导出任务
+type ExportJob struct {
+	ID                uint            `gorm:"primarykey" json:"id"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	UserID            uint            `gorm:"not null;index" json:"user_id"`
+	Status            ExportJobStatus `gorm:"type:varchar(16);not null" json:"status"`
+	Error             string          `gorm:"type:varchar(512)" json:"error,omitempty"`
+	FilePath          string          `gorm:"type:varchar(512)" json:"-"`
+	FileSize          int64           `json:"file_size"`
+	DownloadTokenHash *string         `gorm:"type:char(64);uniqueIndex" json:"-"` // 下载令牌的 SHA-256 摘要，完成前为空
+	CompletedAt       *time.Time      `json:"completed_at"`
+	ExpiresAt         *time.Time      `json:"expires_at"`
+}
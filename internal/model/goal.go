@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// GoalMetric 目标统计的指标
+type GoalMetric string
+
+const (
+	GoalApplications   GoalMetric = "applications"    // 新投递的申请数
+	GoalInterviews     GoalMetric = "interviews"      // 已完成的面试日程数
+	GoalMockInterviews GoalMetric = "mock_interviews" // 已完成的模拟面试日程数
+	GoalEvents         GoalMetric = "events"          // 已完成的全部日程数
+)
+
+// Valid 是否为合法的目标指标
+func (m GoalMetric) Valid() bool {
+	switch m {
+	case GoalApplications, GoalInterviews, GoalMockInterviews, GoalEvents:
+		return true
+	}
+	return false
+}
+
+// Goal 用户设定的周期目标，如“每周投递10份”
+type Goal struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Title     string     `gorm:"type:varchar(64)" json:"title"`
+	Metric    GoalMetric `gorm:"type:varchar(32);not null" json:"metric"`
+	Period    string     `gorm:"type:varchar(16);not null" json:"period"` // day、week 或 month
+	Target    int        `gorm:"not null" json:"target"`
+	Timezone  string     `gorm:"type:varchar(64)" json:"timezone"` // 按该时区划分周期，为空时使用服务器时区
+	Active    bool       `gorm:"not null;default:true" json:"active"`
+
+	LastAchievedPeriod string `gorm:"type:varchar(16)" json:"-"` // 最近一次发送达成通知的周期开始日期，避免重复通知
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// 申请历史动作
+const (
+	HistoryCreate    = "create"     // 新建
+	HistoryStatus    = "status"     // 状态变更
+	HistoryTagAdd    = "tag_add"    // 添加标签
+	HistoryTagRemove = "tag_remove" // 移除标签
+	HistoryArchive   = "archive"    // 归档
+	HistoryUnarchive = "unarchive"  // 取消归档
+	HistoryDelete    = "delete"     // 移入回收站
+	HistoryRestore   = "restore"    // 从回收站恢复
+	HistoryMerge     = "merge"      // 合并重复申请
+	HistoryEventAdd  = "event_add"  // 新增日程
+	HistoryEventDone = "event_done" // 日程已完成
+)
+
+// 历史来源
+const (
+	HistorySourceUser   = "user"   // 用户单条操作
+	HistorySourceBulk   = "bulk"   // 批量操作
+	HistorySourceSystem = "system" // 系统自动处理
+)
+
+// ApplicationHistory 申请的变更历史，状态变更记录用于漏斗等统计
+type ApplicationHistory struct {
+	ID            uint              `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time         `gorm:"index" json:"created_at"`
+	ApplicationID uint              `gorm:"not null;index" json:"application_id"`
+	UserID        uint              `gorm:"not null;index" json:"user_id"`
+	ActorID       uint              `json:"actor_id"`
+	Source        string            `gorm:"type:varchar(16);not null" json:"source"`
+	Action        string            `gorm:"type:varchar(16);not null" json:"action"`
+	FromStatus    ApplicationStatus `gorm:"type:varchar(32)" json:"from_status,omitempty"`
+	ToStatus      ApplicationStatus `gorm:"type:varchar(32)" json:"to_status,omitempty"`
+	Detail        string            `gorm:"type:varchar(255)" json:"detail,omitempty"`
+}
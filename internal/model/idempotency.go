@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// IdempotencyRecord 幂等键记录，保存首次请求的响应用于重放
+type IdempotencyRecord struct {
+	ID          uint      `gorm:"primarykey"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key         string    `gorm:"column:idem_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	RequestHash string    `gorm:"type:char(64);not null"`
+	Owner       string    `gorm:"type:char(32);not null;default:''"` // 占用幂等键的请求
+	Completed   bool      `gorm:"not null;default:false"`
+	StatusCode  int
+	ContentType string `gorm:"type:varchar(128)"`
+	Body        []byte `gorm:"type:mediumblob"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// 通知类型
+const (
+	NotificationGoalAchieved = "goal_achieved" // 目标达成
+	NotificationTaskDue      = "task_due"      // 任务即将到期
+)
+
+// NotificationSetting 通知的推送方式，与周报订阅分开设置。每个用户一条，没有记录时只保存站内通知。
+// Channel 取值同周报：none 只保存站内通知，email 同时发送邮件，webhook 同时 POST 到 WebhookURL
+type NotificationSetting struct {
+	UserID        uint      `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Channel       string    `gorm:"type:varchar(16);not null;default:'none'" json:"channel"`
+	WebhookURL    string    `gorm:"type:varchar(512)" json:"webhook_url"`
+	WebhookSecret string    `gorm:"type:varchar(64);not null;default:''" json:"webhook_secret,omitempty"`
+}
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    uint       `gorm:"not null;index:idx_notifications_user_read" json:"user_id"`
+	Kind      string     `gorm:"type:varchar(32);not null" json:"kind"`
+	Title     string     `gorm:"type:varchar(128);not null" json:"title"`
+	Body      string     `gorm:"type:varchar(1024)" json:"body"`
+	ReadAt    *time.Time `gorm:"index:idx_notifications_user_read" json:"read_at"`
+}
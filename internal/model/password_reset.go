@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// PasswordResetToken 管理员发起的密码重置令牌，用户通过邮件中的链接设置新密码
+type PasswordResetToken struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	TokenHash  string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuestionTopic 面试题的分类
+type QuestionTopic string
+
+const (
+	TopicAlgorithm    QuestionTopic = "algorithm"     // 算法与数据结构
+	TopicSystemDesign QuestionTopic = "system_design" // 系统设计
+	TopicBehavioral   QuestionTopic = "behavioral"    // 行为面试
+	TopicFundamentals QuestionTopic = "fundamentals"  // 计算机基础（网络、操作系统、数据库等）
+	TopicProject      QuestionTopic = "project"       // 项目经历
+	TopicOther        QuestionTopic = "other"         // 其他
+)
+
+// Valid 是否为合法的分类
+func (t QuestionTopic) Valid() bool {
+	switch t {
+	case TopicAlgorithm, TopicSystemDesign, TopicBehavioral, TopicFundamentals, TopicProject, TopicOther:
+		return true
+	}
+	return false
+}
+
+// TopicList 分类列表，在数据库中以逗号分隔的字符串保存
+type TopicList []QuestionTopic
+
+// Value 实现 driver.Valuer
+func (l TopicList) Value() (driver.Value, error) {
+	parts := make([]string, len(l))
+	for i, topic := range l {
+		parts[i] = string(topic)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan 实现 sql.Scanner
+func (l *TopicList) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case nil:
+	default:
+		return fmt.Errorf("无法将 %T 转换为 TopicList", value)
+	}
+
+	*l = nil
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, QuestionTopic(part))
+		}
+	}
+	return nil
+}
+
+// InterviewQuestion 面试中被问到的题目及自己的回答和复盘，来自某条申请，可关联到具体的面试日程
+type InterviewQuestion struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	ApplicationID uint      `gorm:"not null;index" json:"application_id"`
+	EventID       *uint     `gorm:"index" json:"event_id"` // 所在的面试轮次，为空表示未关联日程
+	Question      string    `gorm:"type:text;not null" json:"question"`
+	Answer        string    `gorm:"type:text" json:"answer,omitempty"`     // 当时的回答或参考答案
+	Reflection    string    `gorm:"type:text" json:"reflection,omitempty"` // 复盘
+	Topics        TopicList `gorm:"type:varchar(128);not null" json:"topics"`
+}
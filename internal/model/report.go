@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// 周报的发送方式
+const (
+	DigestChannelNone    = "none"    // 不发送
+	DigestChannelEmail   = "email"   // 发送到账号邮箱
+	DigestChannelWebhook = "webhook" // POST 到用户配置的地址
+)
+
+// DigestSubscription 用户的周报订阅设置，每个用户一条，没有记录时不发送
+type DigestSubscription struct {
+	UserID     uint      `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Channel    string    `gorm:"type:varchar(16);not null;default:'none'" json:"channel"`
+	WebhookURL string    `gorm:"type:varchar(512)" json:"webhook_url"`
+	// WebhookSecret webhook 签名密钥，选择 webhook 时生成，接收方用它验证请求确实来自本系统
+	WebhookSecret string `gorm:"type:varchar(64);not null;default:''" json:"webhook_secret,omitempty"`
+	Timezone      string `gorm:"type:varchar(64)" json:"timezone"`       // 按该时区划分周，为空时使用服务器时区
+	LastSentWeek  string `gorm:"type:varchar(10)" json:"last_sent_week"` // 最近一次发送的周，如 2026-W42
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 停滞申请的自动处理方式
+const (
+	StaleActionNone     = "none"     // 只提示，不自动处理
+	StaleActionRejected = "rejected" // 自动改为已拒绝
+	StaleActionGhosted  = "ghosted"  // 自动改为无回复
+)
+
+// StatusList 状态列表，在数据库中以逗号分隔的字符串保存
+type StatusList []ApplicationStatus
+
+// Value 实现 driver.Valuer
+func (l StatusList) Value() (driver.Value, error) {
+	parts := make([]string, len(l))
+	for i, status := range l {
+		parts[i] = string(status)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan 实现 sql.Scanner
+func (l *StatusList) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case nil:
+	default:
+		return fmt.Errorf("无法将 %T 转换为 StatusList", value)
+	}
+
+	*l = nil
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, ApplicationStatus(part))
+		}
+	}
+	return nil
+}
+
+// StaleRule 用户的停滞规则：申请在指定状态下超过 StaleDays 天没有任何修改视为停滞，
+// Action 不为 none 时超过 ActionDays 天由系统自动修改状态。每个用户一条，没有记录时使用默认规则
+type StaleRule struct {
+	UserID     uint       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	StaleDays  int        `gorm:"not null;default:30" json:"stale_days"`
+	Statuses   StatusList `gorm:"type:varchar(128);not null" json:"statuses"` // 参与判断的状态
+	Action     string     `gorm:"type:varchar(16);not null;default:'none'" json:"action"`
+	ActionDays int        `gorm:"not null;default:0" json:"action_days"` // 自动处理的天数，不小于 StaleDays
+}
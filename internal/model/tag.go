@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Tag 用户自定义标签
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_tags_user_name" json:"user_id"`
+	Name      string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_tags_user_name" json:"name"`
+}
+
+// ApplicationTag 申请与标签的关联
+type ApplicationTag struct {
+	ApplicationID uint      `gorm:"primaryKey" json:"application_id"`
+	TagID         uint      `gorm:"primaryKey;index" json:"tag_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
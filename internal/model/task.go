@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// 任务来源
+const (
+	TaskSourceUser = "user" // 用户手动创建
+	TaskSourceAuto = "auto" // 状态变化、日程完成时自动创建
+)
+
+// Task 待办任务，可关联到某条申请
+type Task struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UserID        uint       `gorm:"not null;index:idx_tasks_user_due;uniqueIndex:idx_tasks_user_auto_key" json:"user_id"`
+	ApplicationID *uint      `gorm:"index" json:"application_id"`
+	Title         string     `gorm:"type:varchar(128);not null" json:"title"`
+	Notes         string     `gorm:"type:text" json:"notes,omitempty"`
+	DueAt         *time.Time `gorm:"index:idx_tasks_user_due" json:"due_at"` // 截止时间，为空表示没有期限
+	Completed     bool       `gorm:"not null;default:false" json:"completed"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	Source        string     `gorm:"type:varchar(16);not null;default:'user'" json:"source"`
+
+	AutoKey    *string    `gorm:"type:varchar(64);uniqueIndex:idx_tasks_user_auto_key" json:"-"` // 自动创建任务的去重键，同一触发来源只创建一次
+	RemindedAt *time.Time `json:"-"`                                                             // 到期提醒发送时间，修改截止时间后清空
+}
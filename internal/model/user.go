@@ -6,6 +6,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// 用户角色
+const (
+	RoleUser  = "user"  // 普通用户
+	RoleAdmin = "admin" // 管理员
+)
+
+// 性别
+const (
+	GenderMale   = "male"   // 男
+	GenderFemale = "female" // 女
+	GenderOther  = "other"  // 其他
+)
+
 type User struct {
 	gorm.Model
 	Username    string     `gorm:"type:varchar(32);uniqueIndex;not null" json:"username"`
@@ -14,5 +27,14 @@ type User struct {
 	Age         int        `json:"age"`
 	Gender      string     `json:"gender"`
 	Phone       string     `json:"phone"`
+	Role        string     `gorm:"type:varchar(16);not null;default:user" json:"role"`
+	Disabled    bool       `gorm:"not null;default:false" json:"disabled"`
 	LastLoginAt *time.Time `json:"last_login_at"`
+	Version     uint       `gorm:"not null;default:1" json:"version"` // 乐观锁版本号，每次修改递增
+
+	InsightsOptOut bool `gorm:"not null;default:false" json:"insights_opt_out"` // 不参与公司统计（响应时间、面试率等）
+
+	// 注销申请时间和计划清除时间，冷静期内可撤销
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at"`
 }
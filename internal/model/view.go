@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// SavedView 用户保存的申请列表视图（筛选条件和排序）
+type SavedView struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_views_user_name" json:"user_id"`
+	Name       string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_views_user_name" json:"name"`
+	Filter     string    `gorm:"type:varchar(1024)" json:"filter"`   // 高级筛选表达式
+	Search     string    `gorm:"type:varchar(256)" json:"search"`    // 全文检索条件
+	Sort       string    `gorm:"type:varchar(16)" json:"sort"`       // 排序字段，为空时使用默认排序
+	SortDesc   bool      `gorm:"not null;default:false" json:"desc"` // 是否倒序
+	Archived   bool      `gorm:"not null;default:false" json:"archived"`
+	Pinned     bool      `gorm:"not null;default:false" json:"pinned"`                      // 置顶
+	ShareToken *string   `gorm:"type:varchar(64);uniqueIndex" json:"share_token,omitempty"` // 分享令牌，为空表示未分享
+}
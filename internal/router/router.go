@@ -3,6 +3,7 @@ package router
 import (
 	"internship-manager/internal/handler"
 	"internship-manager/internal/middleware"
+	"internship-manager/internal/model"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -16,21 +17,41 @@ func SetupRouter() *gin.Engine {
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"*"} // 允许所有源访问
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Accept", "Authorization", "X-Requested-With"}
-	config.ExposeHeaders = []string{"Content-Length"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Accept", "Authorization", "X-Requested-With", middleware.RequestIDHeader}
+	config.AllowHeaders = append(config.AllowHeaders, "If-Match", middleware.IdempotencyKeyHeader)
+	config.ExposeHeaders = []string{"Content-Length", "ETag", "Idempotent-Replayed", middleware.RequestIDHeader}
 	r.Use(cors.New(config))
+	r.Use(middleware.RequestID())
 
 	// 创建处理器实例
 	userHandler := handler.NewUserHandler()
 	applicationHandler := handler.NewApplicationHandler()
+	adminHandler := handler.NewAdminHandler()
+	auditHandler := handler.NewAuditHandler()
+	tagHandler := handler.NewTagHandler()
+	viewHandler := handler.NewViewHandler()
+	analyticsHandler := handler.NewAnalyticsHandler()
+	reportHandler := handler.NewReportHandler()
+	goalHandler := handler.NewGoalHandler()
+	taskHandler := handler.NewTaskHandler()
+	questionHandler := handler.NewQuestionHandler()
+	experienceHandler := handler.NewExperienceHandler()
+	insightHandler := handler.NewInsightHandler()
+	notificationHandler := handler.NewNotificationHandler()
 
 	// 公开路由
 	auth := r.Group("/api/auth")
 	{
 		auth.POST("/register", userHandler.Register)
 		auth.POST("/login", userHandler.Login)
+		//确认新邮箱（邮件链接中的令牌）
+		auth.POST("/email/verify", userHandler.ConfirmEmailChange)
+		auth.POST("/password/reset", userHandler.ConfirmPasswordReset)
 	}
 
+	// 数据导出下载（限时令牌）
+	r.GET("/api/export/download/:token", userHandler.DownloadExport)
+
 	// 需要认证的路由
 	authorized := r.Group("/api")
 	authorized.Use(middleware.JWTAuth())
@@ -48,6 +69,18 @@ func SetupRouter() *gin.Engine {
 			user.PUT("/:id", userHandler.UpdateProfile) // 新的更新路由
 			//删除
 			user.DELETE("/:id", userHandler.DeleteAccount)
+			//撤销注销
+			user.POST("/:id/deletion/cancel", userHandler.CancelDeletion)
+			//导出个人数据
+			user.GET("/export", userHandler.ExportData)
+			//异步导出ZIP
+			user.POST("/export", userHandler.StartExport)
+			//导出任务状态
+			user.GET("/export/:id", userHandler.GetExport)
+			//申请修改邮箱
+			user.POST("/email/change", userHandler.RequestEmailChange)
+			//操作记录
+			user.GET("/audit", auditHandler.GetMyAuditEvents)
 
 		}
 
@@ -58,9 +91,13 @@ func SetupRouter() *gin.Engine {
 			//分页查找 带筛选和搜索
 			applications.GET("", applicationHandler.GetApplications)
 			//新增
-			applications.POST("", applicationHandler.CreateApplication)
+			applications.POST("", middleware.Idempotency(), applicationHandler.CreateApplication)
 			//修改
 			applications.PUT("/:id", applicationHandler.UpdateApplication) // 新的更新路由
+			//部分更新（JSON Merge Patch）
+			applications.PATCH("/:id", applicationHandler.PatchApplication)
+			//详情
+			applications.GET("/:id", applicationHandler.GetApplication)
 			//删除
 			applications.DELETE("/:id", applicationHandler.DeleteApplication)
 
@@ -71,6 +108,168 @@ func SetupRouter() *gin.Engine {
 			//更新状态
 			applications.PATCH("/status", applicationHandler.UpdateStatus)
 
+			//回收站
+			applications.GET("/trash", applicationHandler.GetTrash)
+			//清空回收站
+			applications.DELETE("/trash", applicationHandler.EmptyTrash)
+			//恢复
+			applications.POST("/:id/restore", applicationHandler.RestoreApplication)
+			//永久删除
+			applications.DELETE("/:id/purge", applicationHandler.PurgeApplication)
+
+			//批量操作
+			applications.POST("/bulk", applicationHandler.BulkUpdate)
+			//变更历史
+			applications.GET("/:id/history", applicationHandler.GetHistory)
+			//疑似重复的申请
+			applications.GET("/duplicates", applicationHandler.GetDuplicates)
+			//合并重复申请
+			applications.POST("/merge", applicationHandler.MergeApplications)
+			//笔试、面试等日程
+			applications.GET("/upcoming-events", applicationHandler.GetUpcomingEvents)
+			applications.GET("/:id/events", applicationHandler.GetEvents)
+			applications.POST("/:id/events", applicationHandler.CreateEvent)
+			applications.PATCH("/:id/events/:eventId", applicationHandler.UpdateEvent)
+			applications.DELETE("/:id/events/:eventId", applicationHandler.DeleteEvent)
+			//长期无进展的申请及判断规则
+			applications.GET("/stale", applicationHandler.GetStaleApplications)
+			applications.GET("/stale/rule", applicationHandler.GetStaleRule)
+			applications.PUT("/stale/rule", applicationHandler.UpdateStaleRule)
+			//各轮面试的题目
+			applications.GET("/:id/questions", questionHandler.GetApplicationQuestions)
+
+		}
+
+		// 标签
+		authorized.GET("/tags", tagHandler.GetTags)
+
+		// 统计分析
+		analytics := authorized.Group("/analytics")
+		{
+			analytics.GET("/funnel", analyticsHandler.GetFunnel)
+			analytics.GET("/timeseries", analyticsHandler.GetTimeseries)
+		}
+
+		// 报告
+		reports := authorized.Group("/reports")
+		{
+			reports.GET("/weekly", reportHandler.GetWeeklyReport)
+			//周报订阅（邮件或 webhook）
+			reports.GET("/weekly/subscription", reportHandler.GetSubscription)
+			reports.PUT("/weekly/subscription", reportHandler.UpdateSubscription)
+			//求职总结 PDF
+			reports.GET("/summary.pdf", reportHandler.GetSummaryPDF)
+		}
+
+		// 目标
+		goals := authorized.Group("/goals")
+		{
+			goals.GET("", goalHandler.GetGoals)
+			goals.POST("", goalHandler.CreateGoal)
+			//当前周期进度和连续达成次数
+			goals.GET("/progress", goalHandler.GetProgress)
+			goals.PUT("/:id", goalHandler.UpdateGoal)
+			goals.DELETE("/:id", goalHandler.DeleteGoal)
+		}
+
+		// 不关联申请的模拟面试，计入模拟面试目标
+		mockInterviews := authorized.Group("/mock-interviews")
+		{
+			mockInterviews.GET("", applicationHandler.GetMockInterviews)
+			mockInterviews.POST("", applicationHandler.CreateMockInterview)
+			mockInterviews.PATCH("/:id", applicationHandler.UpdateMockInterview)
+			mockInterviews.DELETE("/:id", applicationHandler.DeleteMockInterview)
+		}
+
+		// 待办任务
+		tasks := authorized.Group("/tasks")
+		{
+			tasks.GET("", taskHandler.GetTasks)
+			tasks.POST("", taskHandler.CreateTask)
+			tasks.PATCH("/:id", taskHandler.UpdateTask)
+			tasks.DELETE("/:id", taskHandler.DeleteTask)
+		}
+
+		// 面试题库
+		questions := authorized.Group("/questions")
+		{
+			questions.GET("", questionHandler.GetQuestions)
+			questions.POST("", questionHandler.CreateQuestion)
+			questions.PUT("/:id", questionHandler.UpdateQuestion)
+			questions.DELETE("/:id", questionHandler.DeleteQuestion)
+		}
+
+		// 匿名面经
+		experiences := authorized.Group("/experiences")
+		{
+			experiences.GET("", experienceHandler.GetExperiences)
+			experiences.POST("", experienceHandler.PublishExperience)
+			//自己发布的
+			experiences.GET("/mine", experienceHandler.GetMyExperiences)
+			//根据申请生成草稿
+			experiences.GET("/draft", experienceHandler.GetDraft)
+			experiences.GET("/:id", experienceHandler.GetExperience)
+			experiences.DELETE("/:id", experienceHandler.DeleteExperience)
+			//点赞与取消点赞
+			experiences.POST("/:id/upvote", experienceHandler.Upvote)
+			experiences.DELETE("/:id/upvote", experienceHandler.RemoveUpvote)
+			//举报
+			experiences.POST("/:id/report", experienceHandler.ReportExperience)
+		}
+
+		// 公司统计（汇总所有用户的申请）
+		companies := authorized.Group("/companies")
+		{
+			companies.GET("", insightHandler.GetCompanies)
+			companies.GET("/:id/insights", insightHandler.GetCompanyInsights)
+		}
+
+		// 站内通知
+		notifications := authorized.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.POST("/read-all", notificationHandler.MarkAllRead)
+			//推送方式（邮件或 webhook），与周报订阅分开设置
+			notifications.GET("/settings", notificationHandler.GetSetting)
+			notifications.PUT("/settings", notificationHandler.UpdateSetting)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// 保存的视图
+		views := authorized.Group("/views")
+		{
+			views.GET("", viewHandler.GetViews)
+			views.POST("", viewHandler.CreateView)
+			views.PUT("/:id", viewHandler.UpdateView)
+			views.DELETE("/:id", viewHandler.DeleteView)
+			//分享与取消分享
+			views.POST("/:id/share", viewHandler.ShareView)
+			views.DELETE("/:id/share", viewHandler.UnshareView)
+			//查看、保存别人分享的视图
+			views.GET("/shared/:token", viewHandler.GetSharedView)
+			views.POST("/shared/:token/copy", viewHandler.CopySharedView)
+		}
+
+		// 管理员路由
+		admin := authorized.Group("/admin")
+		admin.Use(middleware.RequireRole(model.RoleAdmin))
+		{
+			//用户列表 带搜索
+			admin.GET("/users", adminHandler.ListUsers)
+			//禁用账号
+			admin.POST("/users/:id/disable", adminHandler.DisableUser)
+			//启用账号
+			admin.POST("/users/:id/enable", adminHandler.EnableUser)
+			//重置密码
+			admin.POST("/users/:id/reset-password", adminHandler.ResetPassword)
+			//系统统计
+			admin.GET("/stats", adminHandler.GetStats)
+			//审计日志
+			admin.GET("/audit", auditHandler.GetAllAuditEvents)
+			//面经审核队列
+			admin.GET("/experiences/reports", experienceHandler.GetReportedExperiences)
+			//处理举报
+			admin.POST("/experiences/:id/moderate", experienceHandler.ModerateExperience)
 		}
 	}
 
@@ -0,0 +1,13 @@
+package search
+
+// Hit 一条命中结果
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// Backend 申请记录的全文检索后端
+type Backend interface {
+	// Search 在用户未删除的申请中检索，按相关度从高到低返回最多 limit 条
+	Search(userID uint, query Query, limit int) ([]Hit, error)
+}
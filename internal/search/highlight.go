@@ -0,0 +1,99 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// 摘要中命中词前后保留的字符数
+const snippetContext = 30
+
+// 高亮标记
+const (
+	HighlightPre  = "<em>"
+	HighlightPost = "</em>"
+)
+
+// Highlight 生成带高亮的摘要：截取第一个命中词附近的文本，命中词用 <em> 包裹，
+// 其余内容做 HTML 转义。没有命中时返回空字符串
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 大小写转换改变了长度（极少见），退化为区分大小写匹配
+		lower = runes
+	}
+
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		needle := []rune(strings.ToLower(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(needle)], needle) {
+				for j := i; j < i+len(needle); j++ {
+					marked[j] = true
+				}
+				if first < 0 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := first - snippetContext
+	if start < 0 {
+		start = 0
+	}
+	end := first + snippetContext*2
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(collapseSpace(string(runes[i:j])))
+		if marked[i] {
+			b.WriteString(HighlightPre + segment + HighlightPost)
+		} else {
+			b.WriteString(segment)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// collapseSpace 将换行等空白替换为空格，保证摘要为单行
+func collapseSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
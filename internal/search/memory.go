@@ -0,0 +1,259 @@
+package search
+
+import (
+	"container/list"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+const (
+	// 最多缓存的用户索引数，超出时淘汰最久未使用的
+	maxIndexedUsers = 1000
+	// 索引闲置超过该时长后淘汰，下次检索时重建
+	indexIdleTTL = 30 * time.Minute
+)
+
+// MemoryBackend 纯 Go 实现的内嵌倒排索引，不依赖 MySQL FULLTEXT（ngram 解析器），适用于未建 FULLTEXT 索引的开发环境。
+// 索引按用户懒加载：每次检索前比较该用户申请数据的指纹，变化时重建该用户的索引；
+// 缓存的索引按最近使用淘汰，并在闲置 indexIdleTTL 后释放
+type MemoryBackend struct {
+	db *gorm.DB
+
+	mu    sync.Mutex
+	users map[uint]*list.Element // 值为 *cachedIndex
+	lru   *list.List             // 最近使用的在前
+}
+
+type cachedIndex struct {
+	userID   uint
+	index    *userIndex
+	lastUsed time.Time
+}
+
+func NewMemoryBackend(db *gorm.DB) *MemoryBackend {
+	return &MemoryBackend{db: db, users: make(map[uint]*list.Element), lru: list.New()}
+}
+
+// fingerprint 用户申请数据的指纹：新增、删除、恢复、永久删除都会改变条数，
+// 每次修改都会递增版本号
+type fingerprint struct {
+	Total      int64
+	Deleted    int64
+	VersionSum int64
+}
+
+type document struct {
+	id     uint
+	fields map[string]string // 转小写后的字段内容
+	length map[string]int    // 各字段的词数
+}
+
+type userIndex struct {
+	fingerprint fingerprint
+	docs        map[uint]*document
+	// postings[field][token][docID] = 词频
+	postings map[string]map[string]map[uint]int
+	avgLen   map[string]float64
+}
+
+func (b *MemoryBackend) Search(userID uint, query Query, limit int) ([]Hit, error) {
+	idx, err := b.index(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []Hit
+	for _, doc := range idx.docs {
+		score, ok := idx.score(doc, query)
+		if ok {
+			hits = append(hits, Hit{ID: doc.id, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// index 返回用户的最新索引，数据有变化时重建
+func (b *MemoryBackend) index(userID uint) (*userIndex, error) {
+	var fp fingerprint
+	if err := b.db.Table("applications").
+		Select("COUNT(*) AS total, COUNT(deleted_at) AS deleted, COALESCE(SUM(version), 0) AS version_sum").
+		Where("user_id = ?", userID).
+		Scan(&fp).Error; err != nil {
+		return nil, err
+	}
+
+	idx, ok := b.cached(userID)
+	if ok && idx.fingerprint == fp {
+		return idx, nil
+	}
+
+	var rows []struct {
+		ID       uint
+		Company  string
+		Position string
+		Notes    string
+	}
+	if err := b.db.Table("applications").
+		Select("id, company, position, notes").
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	idx = &userIndex{
+		fingerprint: fp,
+		docs:        make(map[uint]*document, len(rows)),
+		postings:    make(map[string]map[string]map[uint]int),
+		avgLen:      make(map[string]float64),
+	}
+	for _, row := range rows {
+		idx.add(row.ID, map[string]string{
+			FieldCompany:  row.Company,
+			FieldPosition: row.Position,
+			FieldNotes:    row.Notes,
+		})
+	}
+	for _, field := range Fields {
+		if len(idx.docs) > 0 {
+			idx.avgLen[field] /= float64(len(idx.docs))
+		}
+	}
+
+	b.store(userID, idx)
+	return idx, nil
+}
+
+// cached 取出缓存的索引并标记为最近使用，同时淘汰闲置过久的索引
+func (b *MemoryBackend) cached(userID uint) (*userIndex, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.evictIdle(now)
+	elem, ok := b.users[userID]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedIndex)
+	entry.lastUsed = now
+	b.lru.MoveToFront(elem)
+	return entry.index, true
+}
+
+// store 缓存用户的索引，超出数量上限时淘汰最久未使用的
+func (b *MemoryBackend) store(userID uint, idx *userIndex) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if elem, ok := b.users[userID]; ok {
+		entry := elem.Value.(*cachedIndex)
+		entry.index = idx
+		entry.lastUsed = now
+		b.lru.MoveToFront(elem)
+		return
+	}
+	b.users[userID] = b.lru.PushFront(&cachedIndex{userID: userID, index: idx, lastUsed: now})
+	for b.lru.Len() > maxIndexedUsers {
+		b.remove(b.lru.Back())
+	}
+}
+
+// evictIdle 淘汰闲置超过 indexIdleTTL 的索引，调用方需持有锁
+func (b *MemoryBackend) evictIdle(now time.Time) {
+	for elem := b.lru.Back(); elem != nil; elem = b.lru.Back() {
+		if now.Sub(elem.Value.(*cachedIndex).lastUsed) < indexIdleTTL {
+			return
+		}
+		b.remove(elem)
+	}
+}
+
+func (b *MemoryBackend) remove(elem *list.Element) {
+	b.lru.Remove(elem)
+	delete(b.users, elem.Value.(*cachedIndex).userID)
+}
+
+func (idx *userIndex) add(id uint, fields map[string]string) {
+	doc := &document{id: id, fields: make(map[string]string), length: make(map[string]int)}
+	for field, text := range fields {
+		doc.fields[field] = strings.ToLower(text)
+		tokens := Tokenize(text)
+		doc.length[field] = len(tokens)
+		idx.avgLen[field] += float64(len(tokens))
+
+		if idx.postings[field] == nil {
+			idx.postings[field] = make(map[string]map[uint]int)
+		}
+		for _, token := range tokens {
+			if idx.postings[field][token] == nil {
+				idx.postings[field][token] = make(map[uint]int)
+			}
+			idx.postings[field][token][id]++
+		}
+	}
+	idx.docs[id] = doc
+}
+
+// score 计算文档得分：每个检索词都必须在其作用的某个字段中以子串形式出现，
+// 得分为各字段 BM25 得分乘以字段权重之和
+func (idx *userIndex) score(doc *document, query Query) (float64, bool) {
+	total := 0.0
+	for _, term := range query.Terms {
+		text := strings.ToLower(term.Text)
+		tokens := Tokenize(term.Text)
+		matched := false
+		for _, field := range Fields {
+			if !term.Matches(field) || !strings.Contains(doc.fields[field], text) {
+				continue
+			}
+			matched = true
+			total += idx.bm25(doc, field, tokens) * fieldBoosts[field]
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+func (idx *userIndex) bm25(doc *document, field string, tokens []string) float64 {
+	n := float64(len(idx.docs))
+	score := 0.0
+	for _, token := range tokens {
+		postings := idx.postings[field][token]
+		tf := float64(postings[doc.id])
+		if tf == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		norm := 1 - bm25B
+		if idx.avgLen[field] > 0 {
+			norm += bm25B * float64(doc.length[field]) / idx.avgLen[field]
+		}
+		score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+	return score
+}
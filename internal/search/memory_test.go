@@ -0,0 +1,43 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryBackendEvictsLeastRecentlyUsed(t *testing.T) {
+	b := NewMemoryBackend(nil)
+	for id := uint(1); id <= maxIndexedUsers; id++ {
+		b.store(id, &userIndex{})
+	}
+	// 用户1最近被使用，淘汰的应是用户2
+	if _, ok := b.cached(1); !ok {
+		t.Fatal("用户1的索引不应被淘汰")
+	}
+	b.store(maxIndexedUsers+1, &userIndex{})
+
+	if len(b.users) != maxIndexedUsers || b.lru.Len() != maxIndexedUsers {
+		t.Fatalf("缓存数量应为 %d，实际 %d/%d", maxIndexedUsers, len(b.users), b.lru.Len())
+	}
+	if _, ok := b.cached(2); ok {
+		t.Fatal("最久未使用的用户2应被淘汰")
+	}
+	if _, ok := b.cached(1); !ok {
+		t.Fatal("用户1的索引不应被淘汰")
+	}
+}
+
+func TestMemoryBackendEvictsIdleIndexes(t *testing.T) {
+	b := NewMemoryBackend(nil)
+	b.store(1, &userIndex{})
+	b.store(2, &userIndex{})
+	b.users[1].Value.(*cachedIndex).lastUsed = time.Now().Add(-indexIdleTTL - time.Second)
+	b.lru.MoveToBack(b.users[1])
+
+	if _, ok := b.cached(2); !ok {
+		t.Fatal("用户2的索引未闲置，不应被淘汰")
+	}
+	if _, ok := b.users[1]; ok {
+		t.Fatal("闲置过久的用户1索引应被淘汰")
+	}
+}
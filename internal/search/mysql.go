@@ -0,0 +1,104 @@
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// ngram 解析器的最小词长（MySQL 默认 ngram_token_size=2），更短的检索词改用 LIKE 匹配
+const ngramTokenSize = 2
+
+// 各字段对应的 FULLTEXT 索引列，MATCH 的列必须与索引定义完全一致
+var fulltextColumns = map[string]string{
+	"":            "company, position, notes",
+	FieldCompany:  "company",
+	FieldPosition: "position",
+	FieldNotes:    "notes",
+}
+
+// 字段权重：公司、职位命中比备注命中更相关
+var fieldBoosts = map[string]float64{
+	"":            1,
+	FieldCompany:  3,
+	FieldPosition: 2,
+	FieldNotes:    1,
+}
+
+// MySQLBackend 基于 MySQL FULLTEXT（ngram 解析器）的检索后端
+type MySQLBackend struct {
+	db *gorm.DB
+}
+
+func NewMySQLBackend(db *gorm.DB) *MySQLBackend {
+	return &MySQLBackend{db: db}
+}
+
+func (b *MySQLBackend) Search(userID uint, query Query, limit int) ([]Hit, error) {
+	// 同一字段的检索词合并到一个 MATCH 中，布尔模式下以 + 表示必须命中
+	against := make(map[string][]string)
+	var fields []string
+	var likeConds []string
+	var likeArgs []interface{}
+	for _, term := range query.Terms {
+		text := strings.ReplaceAll(term.Text, `"`, "")
+		if utf8.RuneCountInString(text) < ngramTokenSize {
+			column := term.Field
+			if column == "" {
+				likeConds = append(likeConds, "(company LIKE ? OR position LIKE ? OR notes LIKE ?)")
+				pattern := "%" + EscapeLike(text) + "%"
+				likeArgs = append(likeArgs, pattern, pattern, pattern)
+			} else {
+				likeConds = append(likeConds, column+" LIKE ?")
+				likeArgs = append(likeArgs, "%"+EscapeLike(text)+"%")
+			}
+			continue
+		}
+		if _, ok := against[term.Field]; !ok {
+			fields = append(fields, term.Field)
+		}
+		against[term.Field] = append(against[term.Field], `+"`+text+`"`)
+	}
+
+	var scoreParts []string
+	var scoreArgs []interface{}
+	var matchConds []string
+	var matchArgs []interface{}
+	for _, field := range fields {
+		expr := "MATCH(" + fulltextColumns[field] + ") AGAINST(? IN BOOLEAN MODE)"
+		value := strings.Join(against[field], " ")
+		scoreParts = append(scoreParts, expr+" * ?")
+		scoreArgs = append(scoreArgs, value, fieldBoosts[field])
+		matchConds = append(matchConds, expr)
+		matchArgs = append(matchArgs, value)
+	}
+	score := "0"
+	if len(scoreParts) > 0 {
+		score = strings.Join(scoreParts, " + ")
+	}
+
+	q := b.db.Table("applications").
+		Select("id, "+score+" AS score", scoreArgs...).
+		Where("user_id = ? AND deleted_at IS NULL", userID)
+	for _, cond := range matchConds {
+		q = q.Where(cond, matchArgs[0])
+		matchArgs = matchArgs[1:]
+	}
+	for _, cond := range likeConds {
+		n := strings.Count(cond, "?")
+		q = q.Where(cond, likeArgs[:n]...)
+		likeArgs = likeArgs[n:]
+	}
+
+	var hits []Hit
+	if err := q.Order("score DESC, updated_at DESC").Limit(limit).Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// EscapeLike 转义 LIKE 中的通配符
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
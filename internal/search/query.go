@@ -0,0 +1,94 @@
+package search
+
+import (
+	"errors"
+	"internship-manager/pkg/utils"
+	"strings"
+	"unicode/utf8"
+)
+
+// 可以在查询中限定的字段
+const (
+	FieldCompany  = "company"
+	FieldPosition = "position"
+	FieldNotes    = "notes"
+)
+
+// Fields 参与检索的全部字段
+var Fields = []string{FieldCompany, FieldPosition, FieldNotes}
+
+// 查询长度限制
+const (
+	maxQueryLength = 256
+	maxQueryTerms  = 10
+)
+
+var ErrInvalidQuery = errors.New("无效的搜索条件")
+
+// Term 查询中的一个检索词，Field 为空表示检索全部字段
+type Term struct {
+	Field string
+	Text  string
+}
+
+// Query 解析后的查询，各检索词之间为"与"的关系
+type Query struct {
+	Terms []Term
+}
+
+// Texts 返回全部检索词文本，用于生成高亮摘要
+func (q Query) Texts() []string {
+	texts := make([]string, len(q.Terms))
+	for i, t := range q.Terms {
+		texts[i] = t.Text
+	}
+	return texts
+}
+
+// Matches 检索词是否作用于指定字段
+func (t Term) Matches(field string) bool {
+	return t.Field == "" || t.Field == field
+}
+
+// Parse 解析查询字符串。支持：
+//   - 空格分隔的多个检索词：字节 后端
+//   - 双引号包裹的短语："机器 学习"
+//   - 限定字段：position:后端、company:"字节 跳动"
+//
+// 未知的字段前缀按普通文本处理
+func Parse(raw string) (Query, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || utf8.RuneCountInString(raw) > maxQueryLength {
+		return Query{}, ErrInvalidQuery
+	}
+
+	var query Query
+	for _, token := range utils.SplitQuoted(raw) {
+		term := Term{Text: token}
+		if i := strings.Index(token, ":"); i > 0 {
+			field := strings.ToLower(token[:i])
+			if isField(field) {
+				term = Term{Field: field, Text: token[i+1:]}
+			}
+		}
+		term.Text = strings.TrimSpace(strings.Trim(term.Text, `"`))
+		if term.Text == "" {
+			continue
+		}
+		query.Terms = append(query.Terms, term)
+	}
+
+	if len(query.Terms) == 0 || len(query.Terms) > maxQueryTerms {
+		return Query{}, ErrInvalidQuery
+	}
+	return query, nil
+}
+
+func isField(name string) bool {
+	for _, f := range Fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
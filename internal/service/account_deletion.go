@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"log"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrDeletionNotRequested = errors.New("账号未申请注销")
+
+// anonymizedEmailDomain 匿名化账号使用的邮箱域名，用于识别已清除的账号
+const anonymizedEmailDomain = "deleted.invalid"
+
+// RequestDeletion 申请注销账号，需要操作者本人密码确认，冷静期结束后由后台任务清除数据
+func (s *UserService) RequestDeletion(p Principal, id uint, password string) (*model.User, error) {
+	if err := authorizeUser(p, id); err != nil {
+		return nil, err
+	}
+
+	var actor model.User
+	if err := database.DB.First(&actor, p.UserID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(actor.Password), []byte(password)); err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	var user model.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, id).Error; err != nil {
+			return ErrUserNotFound
+		}
+		if user.DeletionScheduledAt != nil {
+			return nil
+		}
+
+		now := time.Now()
+		scheduled := now.Add(config.AccountDeletionGrace)
+		if err := updateUserVersioned(tx, &user, map[string]interface{}{
+			"deletion_requested_at": &now,
+			"deletion_scheduled_at": &scheduled,
+		}); err != nil {
+			return err
+		}
+		user.DeletionRequestedAt = &now
+		user.DeletionScheduledAt = &scheduled
+		return recordAudit(tx, p, model.AuditActionUserDelete, model.AuditTargetUser, id, id,
+			nil, map[string]interface{}{"deletion_scheduled_at": scheduled})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// CancelDeletion 在冷静期内撤销注销
+func (s *UserService) CancelDeletion(p Principal, id uint) error {
+	if err := authorizeUser(p, id); err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.First(&user, id).Error; err != nil {
+			return ErrUserNotFound
+		}
+		if user.DeletionScheduledAt == nil {
+			return ErrDeletionNotRequested
+		}
+
+		if err := updateUserVersioned(tx, &user, map[string]interface{}{
+			"deletion_requested_at": nil,
+			"deletion_scheduled_at": nil,
+		}); err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionUserDeletionCancel, model.AuditTargetUser, id, id, nil, nil)
+	})
+}
+
+// PurgeDeletedAccounts 清除冷静期已结束的账号及其全部数据，
+// 历史遗留的软删除账号也一并清除，避免其占用用户名和邮箱的唯一索引
+func (s *UserService) PurgeDeletedAccounts() error {
+	var ids []uint
+	if err := database.DB.Unscoped().Model(&model.User{}).
+		Where("deletion_scheduled_at <= ? OR (deleted_at IS NOT NULL AND email NOT LIKE ?)", time.Now(), "%@"+anonymizedEmailDomain).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := purgeAccount(id); err != nil {
+			log.Printf("清除账号 %d 失败: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// purgeAccount 在一个事务中清除单个账号的全部数据
+func purgeAccount(id uint) error {
+	// 导出文件不在数据库中，事务提交后再删除，回滚时记录仍能找到对应的文件
+	var exportFiles []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ExportJob{}).Where("user_id = ? AND file_path <> ''", id).
+			Pluck("file_path", &exportFiles).Error; err != nil {
+			return err
+		}
+
+		// 依赖数据先于用户删除
+		if err := tx.Where("application_id IN (?)",
+			tx.Unscoped().Model(&model.Application{}).Select("id").Where("user_id = ?", id)).
+			Delete(&model.ApplicationTag{}).Error; err != nil {
+			return err
+		}
+		if err := purgeUserExperiences(tx, id); err != nil {
+			return err
+		}
+		for _, dependent := range userOwnedModels() {
+			if err := tx.Unscoped().Where("user_id = ?", id).Delete(dependent).Error; err != nil {
+				return err
+			}
+		}
+
+		if config.AccountPurgeMode == PurgeModeAnonymize {
+			if err := tx.Unscoped().Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"username":              fmt.Sprintf("deleted_%d", id),
+				"email":                 fmt.Sprintf("deleted_%d@%s", id, anonymizedEmailDomain),
+				"password":              "",
+				"age":                   0,
+				"gender":                "",
+				"phone":                 "",
+				"disabled":              true,
+				"deletion_scheduled_at": nil,
+				"deleted_at":            time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Unscoped().Delete(&model.User{}, id).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := redactUserAudit(tx, id); err != nil {
+			return err
+		}
+		return recordAudit(tx, SystemPrincipal, model.AuditActionUserPurge, model.AuditTargetUser, id, id,
+			nil, map[string]interface{}{"mode": config.AccountPurgeMode})
+	})
+	if err != nil {
+		return err
+	}
+
+	// 记录已删除，文件删除失败时只能记录日志，不再重试
+	for _, path := range exportFiles {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("删除账号 %d 的导出文件 %s 失败: %v", id, path, err)
+		}
+	}
+	return nil
+}
+
+// userOwnedModels 按 user_id 归属于用户的数据表，清除账号时需要一并删除
+func userOwnedModels() []interface{} {
+	return []interface{}{
+		&model.ApplicationHistory{},
+		&model.InterviewQuestion{},
+		&model.ApplicationEvent{},
+		&model.Task{},
+		&model.Application{},
+		&model.Tag{},
+		&model.SavedView{},
+		&model.Goal{},
+		&model.Notification{},
+		&model.StaleRule{},
+		&model.DigestSubscription{},
+		&model.NotificationSetting{},
+		&model.EmailChangeRequest{},
+		&model.PasswordResetToken{},
+		&model.ExportJob{},
+		&model.IdempotencyRecord{},
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AdminService struct{}
+
+// ErrModifySelf 管理员不能禁用或启用自己的账号
+var ErrModifySelf = errors.New("不能修改自己的账号状态")
+
+// SystemStats 系统统计信息
+type SystemStats struct {
+	TotalUsers               int64          `json:"total_users"`
+	AdminUsers               int64          `json:"admin_users"`
+	DisabledUsers            int64          `json:"disabled_users"`
+	NewUsersLast7Days        int64          `json:"new_users_last_7_days"`
+	TotalApplications        int64          `json:"total_applications"`
+	NewApplicationsLast7Days int64          `json:"new_applications_last_7_days"`
+	ApplicationsByStatus     map[string]int `json:"applications_by_status"`
+}
+
+// ListUsers 分页查询用户，支持按用户名或邮箱搜索
+func (s *AdminService) ListUsers(p Principal, page, pageSize int, search string) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := database.DB.Model(&model.User{})
+	if search != "" {
+		query = query.Where("username LIKE ? OR email LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id ASC").Limit(pageSize).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := recordAudit(database.DB, p, model.AuditActionAdminListUsers, model.AuditTargetUser, 0, 0, nil, nil); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// SetUserDisabled 禁用或启用账号
+func (s *AdminService) SetUserDisabled(p Principal, targetID uint, disabled bool) error {
+	if p.UserID == targetID {
+		return ErrModifySelf
+	}
+
+	action := model.AuditActionAdminEnableUser
+	if disabled {
+		action = model.AuditActionAdminDisableUser
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Select("id", "disabled", "version").First(&user, targetID).Error; err != nil {
+			return ErrUserNotFound
+		}
+
+		oldDisabled := user.Disabled
+		if err := updateUserVersioned(tx, &user, map[string]interface{}{"disabled": disabled}); err != nil {
+			return err
+		}
+		return recordAudit(tx, p, action, model.AuditTargetUser, targetID, targetID,
+			map[string]interface{}{"disabled": oldDisabled}, map[string]interface{}{"disabled": disabled})
+	})
+}
+
+// GetSystemStats 获取全站统计信息
+func (s *AdminService) GetSystemStats(p Principal) (*SystemStats, error) {
+	stats := &SystemStats{ApplicationsByStatus: make(map[string]int)}
+	weekAgo := time.Now().AddDate(0, 0, -7)
+
+	counts := []struct {
+		query *gorm.DB
+		dest  *int64
+	}{
+		{database.DB.Model(&model.User{}), &stats.TotalUsers},
+		{database.DB.Model(&model.User{}).Where("role = ?", model.RoleAdmin), &stats.AdminUsers},
+		{database.DB.Model(&model.User{}).Where("disabled = ?", true), &stats.DisabledUsers},
+		{database.DB.Model(&model.User{}).Where("created_at >= ?", weekAgo), &stats.NewUsersLast7Days},
+		{database.DB.Model(&model.Application{}), &stats.TotalApplications},
+		{database.DB.Model(&model.Application{}).Where("created_at >= ?", weekAgo), &stats.NewApplicationsLast7Days},
+	}
+	for _, c := range counts {
+		if err := c.query.Count(c.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []struct {
+		Status string `gorm:"column:status"`
+		Count  int    `gorm:"column:count"`
+	}
+	if err := database.DB.Model(&model.Application{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		stats.ApplicationsByStatus[row.Status] = row.Count
+	}
+
+	if err := recordAudit(database.DB, p, model.AuditActionAdminViewStats, model.AuditTargetSystem, 0, 0, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
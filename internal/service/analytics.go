@@ -0,0 +1,215 @@
+package service
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// funnelStages 漏斗各阶段，按流程先后排列；rejected、ghosted 不是阶段，而是从某一阶段流失的去向
+var funnelStages = []model.ApplicationStatus{
+	model.StatusSubmitted,
+	model.StatusWritten,
+	model.StatusInterview,
+	model.StatusAccepted,
+}
+
+type AnalyticsService struct{}
+
+// AnalyticsFilter 分析接口的筛选条件，日期范围作用于申请的创建时间
+type AnalyticsFilter struct {
+	From          *time.Time // 创建时间下限（包含）
+	To            *time.Time // 创建时间上限（不包含）
+	Tag           string
+	Company       string
+	ResumeVersion string
+	// AsOf 按该时刻的状态计算（只看此前创建的申请和此前的状态历史），为空时按当前状态
+	AsOf *time.Time
+}
+
+// FunnelStage 漏斗中的一个阶段
+type FunnelStage struct {
+	Status  model.ApplicationStatus `json:"status"`
+	Reached int                     `json:"reached"` // 曾经到达该阶段（或更后阶段）的申请数
+	// ConversionRate 到达下一阶段的比例，最后一个阶段为 null
+	ConversionRate *float64 `json:"conversion_rate"`
+	DropOff        int      `json:"drop_off"` // 停在该阶段、没有进入下一阶段的申请数
+	Rejected       int      `json:"rejected"` // 其中在该阶段被拒的
+	Ghosted        int      `json:"ghosted"`  // 其中在该阶段长期无回复的
+	Active         int      `json:"active"`   // 其中仍处于该阶段的
+	// MedianHours 在该阶段停留时长的中位数（小时），只统计已离开该阶段的申请
+	MedianHours *float64 `json:"median_hours"`
+}
+
+// Funnel 投递漏斗
+type Funnel struct {
+	Total             int           `json:"total"`
+	Stages            []FunnelStage `json:"stages"`
+	OverallConversion *float64      `json:"overall_conversion"` // 从投递到录用的比例
+}
+
+// analyticsApplications 按分析筛选条件查询操作者未删除的申请（含已归档）
+func analyticsApplications(p Principal, f AnalyticsFilter) (*gorm.DB, error) {
+	db := database.DB.Model(&model.Application{}).Scopes(ownedApplications(p))
+	if f.From != nil {
+		db = db.Where("applications.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		db = db.Where("applications.created_at < ?", *f.To)
+	}
+	if f.Tag != "" {
+		name, err := normalizeTagName(f.Tag)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(`EXISTS (SELECT 1 FROM application_tags JOIN tags ON tags.id = application_tags.tag_id
+			WHERE application_tags.application_id = applications.id AND tags.user_id = ? AND tags.name = ?)`, p.UserID, name)
+	}
+	if f.Company != "" {
+		db = db.Where("applications.company_key = ?", NormalizeCompany(f.Company))
+	}
+	if f.ResumeVersion != "" {
+		db = db.Where("applications.resume_version = ?", f.ResumeVersion)
+	}
+	if f.AsOf != nil {
+		db = db.Where("applications.created_at < ?", *f.AsOf)
+	}
+	return db, nil
+}
+
+// statusChange 状态历史中的一次状态变化
+type statusChange struct {
+	ApplicationID uint
+	ToStatus      model.ApplicationStatus
+	CreatedAt     time.Time
+}
+
+// loadStatusChanges 按申请分组加载状态历史（新建和状态变更），每组按时间先后排列；asOf 不为空时只加载此前的历史
+func loadStatusChanges(p Principal, applications *gorm.DB, asOf *time.Time) (map[uint][]statusChange, error) {
+	query := database.DB.Model(&model.ApplicationHistory{}).
+		Select("application_id, to_status, created_at").
+		Where("user_id = ? AND action IN ? AND to_status <> ''", p.UserID, []string{model.HistoryCreate, model.HistoryStatus}).
+		Where("application_id IN (?)", applications.Select("applications.id"))
+	if asOf != nil {
+		query = query.Where("created_at < ?", *asOf)
+	}
+
+	var rows []statusChange
+	if err := query.
+		Order("application_id ASC, created_at ASC, id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	changes := make(map[uint][]statusChange)
+	for _, row := range rows {
+		changes[row.ApplicationID] = append(changes[row.ApplicationID], row)
+	}
+	return changes, nil
+}
+
+// GetFunnel 根据状态历史计算投递漏斗：各阶段到达数、转化率、流失数和停留时长中位数
+func (s *AnalyticsService) GetFunnel(p Principal, f AnalyticsFilter) (*Funnel, error) {
+	base, err := analyticsApplications(p, f)
+	if err != nil {
+		return nil, err
+	}
+
+	var applications []model.Application
+	if err := base.Session(&gorm.Session{}).Select("applications.id", "applications.status").Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	changes, err := loadStatusChanges(p, base.Session(&gorm.Session{}), f.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	stageIndex := make(map[model.ApplicationStatus]int)
+	for i, status := range funnelStages {
+		stageIndex[status] = i
+	}
+
+	stages := make([]FunnelStage, len(funnelStages))
+	durations := make([][]float64, len(funnelStages))
+	for i, status := range funnelStages {
+		stages[i].Status = status
+	}
+
+	for _, app := range applications {
+		history := changes[app.ID]
+		// 按历史时刻计算时，状态取当时最后一次变化后的状态
+		if f.AsOf != nil && len(history) > 0 {
+			app.Status = history[len(history)-1].ToStatus
+		}
+
+		// 到达的最远阶段：历史中出现过的状态和当前状态取最靠后的
+		furthest := 0
+		if i, ok := stageIndex[app.Status]; ok && i > furthest {
+			furthest = i
+		}
+		for k, change := range history {
+			i, ok := stageIndex[change.ToStatus]
+			if !ok {
+				continue
+			}
+			if i > furthest {
+				furthest = i
+			}
+			// 下一次状态变化即离开该阶段
+			if k+1 < len(history) {
+				hours := history[k+1].CreatedAt.Sub(change.CreatedAt).Hours()
+				durations[i] = append(durations[i], hours)
+			}
+		}
+
+		for i := 0; i <= furthest; i++ {
+			stages[i].Reached++
+		}
+		if furthest < len(funnelStages)-1 {
+			stages[furthest].DropOff++
+			switch app.Status {
+			case model.StatusRejected:
+				stages[furthest].Rejected++
+			case model.StatusGhosted:
+				stages[furthest].Ghosted++
+			default:
+				stages[furthest].Active++
+			}
+		} else if app.Status != model.StatusRejected && app.Status != model.StatusGhosted {
+			stages[furthest].Active++
+		}
+	}
+
+	for i := range stages {
+		if i+1 < len(stages) && stages[i].Reached > 0 {
+			rate := float64(stages[i+1].Reached) / float64(stages[i].Reached)
+			stages[i].ConversionRate = &rate
+		}
+		stages[i].MedianHours = median(durations[i])
+	}
+
+	funnel := &Funnel{Total: len(applications), Stages: stages}
+	if stages[0].Reached > 0 {
+		overall := float64(stages[len(stages)-1].Reached) / float64(stages[0].Reached)
+		funnel.OverallConversion = &overall
+	}
+	return funnel, nil
+}
+
+// median 中位数，没有数据时返回 nil
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	m := sorted[mid]
+	if len(sorted)%2 == 0 {
+		m = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return &m
+}
@@ -6,12 +6,15 @@ import (
 	"gorm.io/gorm"
 	"internship-manager/internal/model"
 	"internship-manager/pkg/database"
+	"time"
 )
 
 type ApplicationService struct{}
 
-// GetRecentApplicationsByUserID 获取用户最近的n条申请记录
-func (s *ApplicationService) GetRecentApplicationsByUserID(userID uint, limit int) ([]model.Application, error) {
+var ErrInvalidStatus = errors.New("无效的申请状态")
+
+// GetRecentApplications 获取操作者最近的n条申请记录
+func (s *ApplicationService) GetRecentApplications(p Principal, limit int) ([]model.Application, error) {
 	var applications []model.Application
 
 	// 正向枚举查询条件
@@ -23,15 +26,14 @@ func (s *ApplicationService) GetRecentApplicationsByUserID(userID uint, limit in
 	}
 
 	// 显式指定查询字段（包含排序需要的updated_at）
-	result := database.DB.Select(
+	result := database.DB.Scopes(ownedApplications(p)).Select(
 		"company",
 		"position",
 		"status",
 		"event_link",
 		"updated_at", // 必须包含排序字段
 	).Where(
-		"user_id = ? AND status IN (?)",
-		userID,
+		"status IN (?)",
 		validStatuses,
 	).Where(
 		"deleted_at IS NULL",
@@ -48,51 +50,198 @@ func (s *ApplicationService) GetRecentApplicationsByUserID(userID uint, limit in
 	return applications, nil
 }
 
-// CreateApplicationFull 创建完整的实习申请记录
-func (s *ApplicationService) CreateApplicationFull(application *model.Application) error {
-	return database.DB.Create(application).Error
+// GetApplication 获取单条申请详情（含标签）
+func (s *ApplicationService) GetApplication(p Principal, id uint) (*model.Application, error) {
+	application, err := findOwnedApplication(database.DB, p, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applications := []model.Application{*application}
+	if err := attachTags(applications); err != nil {
+		return nil, err
+	}
+	return &applications[0], nil
 }
 
-// UpdateApplication 更新申请记录
-func (s *ApplicationService) UpdateApplication(id uint, userID uint, updates map[string]interface{}) error {
-	result := database.DB.Model(&model.Application{}).
-		Where("id = ? AND user_id = ?", id, userID).
-		Updates(updates)
+// ListAllApplications 获取操作者的全部申请记录（含备注），用于数据导出
+func (s *ApplicationService) ListAllApplications(p Principal) ([]model.Application, error) {
+	var applications []model.Application
+	if err := database.DB.Scopes(ownedApplications(p)).Order("id ASC").Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	if err := attachTags(applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// CreateApplicationFull 创建完整的实习申请记录，记录归属于操作者本人。
+// 返回的疑似重复记录仅作提示，不阻止创建
+func (s *ApplicationService) CreateApplicationFull(p Principal, application *model.Application) ([]DuplicateCandidate, error) {
+	application.UserID = p.UserID
+	application.CompanyKey = NormalizeCompany(application.Company)
+	if err := validateApplication(application); err != nil {
+		return nil, err
+	}
+
+	var duplicates []DuplicateCandidate
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		duplicates, err = findDuplicates(tx, p, application.Company, application.Position, 0)
+		if err != nil {
+			return err
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("申请记录不存在或无权限更新")
+		if err := tx.Create(application).Error; err != nil {
+			return err
+		}
+		if err := recordHistory(tx, p, model.HistorySourceUser, model.ApplicationHistory{
+			ApplicationID: application.ID,
+			UserID:        application.UserID,
+			Action:        model.HistoryCreate,
+			ToStatus:      application.Status,
+		}); err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionApplicationCreate, model.AuditTargetApplication, application.ID, application.UserID, nil, application)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return duplicates, nil
+}
 
-	return result.Error
+// UpdateApplication 更新申请记录，expectedVersion 不为 nil 时校验版本号，返回更新后的记录
+func (s *ApplicationService) UpdateApplication(p Principal, id uint, updates map[string]interface{}, expectedVersion *uint) (*model.Application, error) {
+	var after *model.Application
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(expectedVersion, before.Version, before); err != nil {
+			return err
+		}
+
+		// 与 PATCH 使用相同的校验规则
+		updated := *before
+		if err := applyApplicationUpdates(&updated, updates); err != nil {
+			return err
+		}
+		if err := validateApplication(&updated); err != nil {
+			return err
+		}
+
+		current := *before
+		if err := updateApplicationVersioned(tx, &current, updates); err != nil {
+			return err
+		}
+
+		after, err = findOwnedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(tx, p, model.AuditActionApplicationUpdate, model.AuditTargetApplication, id, before.UserID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
 }
 
 // DeleteApplication 删除实习申请记录
-func (s *ApplicationService) DeleteApplication(id uint, userID uint) error {
-	// 先检查记录是否存在且属于该用户
-	var application model.Application
-	result := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&application)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return errors.New("申请记录不存在或无权限删除")
+func (s *ApplicationService) DeleteApplication(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// 先检查记录是否存在且属于该用户
+		application, err := findOwnedApplication(tx, p, id)
+		if err != nil {
+			return err
 		}
-		return result.Error
+		return deleteApplicationTx(tx, p, model.HistorySourceUser, application)
+	})
+}
+
+// deleteApplicationTx 将申请移入回收站，调用方需已完成归属校验
+func deleteApplicationTx(tx *gorm.DB, p Principal, source string, application *model.Application) error {
+	if err := tx.Delete(application).Error; err != nil {
+		return err
+	}
+	if err := recordHistory(tx, p, source, model.ApplicationHistory{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		Action:        model.HistoryDelete,
+	}); err != nil {
+		return err
 	}
+	return recordAudit(tx, p, model.AuditActionApplicationDelete, model.AuditTargetApplication, application.ID, application.UserID, application, nil)
+}
 
-	// 执行删除操作
-	return database.DB.Delete(&application).Error
+// UpdateApplicationStatus 更新状态，expectedVersion 不为 nil 时校验版本号，返回更新后的记录
+func (s *ApplicationService) UpdateApplicationStatus(p Principal, id uint, status model.ApplicationStatus, expectedVersion *uint) (*model.Application, error) {
+	var application *model.Application
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		application, err = findOwnedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(expectedVersion, application.Version, application); err != nil {
+			return err
+		}
+		return setStatusTx(tx, p, model.HistorySourceUser, application, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
 }
 
-// UpdateApplicationStatus 更新状态
-func (s *ApplicationService) UpdateApplicationStatus(id uint, status model.ApplicationStatus) error {
-	result := database.DB.Model(&model.Application{}).Where("id = ?", id).Update("status", status)
-	if result.RowsAffected == 0 {
-		return errors.New("申请记录不存在")
+// setStatusTx 修改申请状态并记录历史，调用方需已完成归属校验；状态未变化时不做任何操作
+func setStatusTx(tx *gorm.DB, p Principal, source string, application *model.Application, status model.ApplicationStatus) error {
+	return setStatusWithDetailTx(tx, p, source, application, status, "")
+}
+
+// setStatusWithDetailTx 同 setStatusTx，detail 写入历史记录说明变更原因
+func setStatusWithDetailTx(tx *gorm.DB, p Principal, source string, application *model.Application, status model.ApplicationStatus, detail string) error {
+	if !status.Valid() {
+		return ErrInvalidStatus
+	}
+
+	oldStatus := application.Status
+	if oldStatus == status {
+		return nil
+	}
+
+	if err := updateApplicationVersioned(tx, application, map[string]interface{}{"status": status}); err != nil {
+		return err
 	}
-	return result.Error
+	application.Status = status
+
+	if err := recordHistory(tx, p, source, model.ApplicationHistory{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		Action:        model.HistoryStatus,
+		FromStatus:    oldStatus,
+		ToStatus:      status,
+		Detail:        detail,
+	}); err != nil {
+		return err
+	}
+	// 用户确认录用后提醒回复 offer，系统自动处理的状态变化不创建任务
+	if status == model.StatusAccepted && source != model.HistorySourceSystem {
+		if err := createAutoTasks(tx, p, application, fmt.Sprintf("status:%d:%s", application.ID, status), time.Now(), acceptedTasks); err != nil {
+			return err
+		}
+	}
+	return recordAudit(tx, p, model.AuditActionApplicationUpdateStatus, model.AuditTargetApplication, application.ID, application.UserID,
+		map[string]interface{}{"status": oldStatus}, map[string]interface{}{"status": status})
 }
 
 // GetApplicationStatistics 获取申请统计信息
-func (s *ApplicationService) GetApplicationStatistics(userID uint) (map[string]int, error) {
+// filter 不为 nil 时按与列表相同的筛选条件统计，保证数字与列表一致
+func (s *ApplicationService) GetApplicationStatistics(p Principal, filter *ApplicationFilter) (map[string]int, error) {
 	var stats = make(map[string]int)
 
 	var result []struct {
@@ -100,6 +249,25 @@ func (s *ApplicationService) GetApplicationStatistics(userID uint) (map[string]i
 		Count  int    `gorm:"column:count"`
 	}
 
+	if filter != nil {
+		f, err := filterApplications(p, "applications", *filter)
+		if err != nil {
+			return nil, err
+		}
+		if !f.empty() {
+			if err := f.db.Select("status, COUNT(*) AS count").Group("status").Scan(&result).Error; err != nil {
+				return nil, err
+			}
+		}
+		for _, row := range result {
+			stats[row.Status] = row.Count
+		}
+		for _, status := range []model.ApplicationStatus{model.StatusAccepted, model.StatusRejected, model.StatusGhosted, model.StatusInterview, model.StatusWritten, model.StatusSubmitted} {
+			stats[string(status)] = stats[string(status)]
+		}
+		return stats, nil
+	}
+
 	err := database.DB.Raw(`
         SELECT 
             status, 
@@ -108,11 +276,11 @@ func (s *ApplicationService) GetApplicationStatistics(userID uint) (map[string]i
             applications 
         WHERE 
             user_id = ? 
-            AND status IN (?, ?, ?, ?,?) 
+            AND status IN (?, ?, ?, ?, ?, ?) 
             AND deleted_at IS NULL 
         GROUP BY 
             status
-    `, userID, model.StatusAccepted, model.StatusRejected, model.StatusInterview, model.StatusWritten, model.StatusSubmitted).Scan(&result).Error
+    `, p.UserID, model.StatusAccepted, model.StatusRejected, model.StatusGhosted, model.StatusInterview, model.StatusWritten, model.StatusSubmitted).Scan(&result).Error
 
 	if err != nil {
 		return nil, err
@@ -127,6 +295,7 @@ func (s *ApplicationService) GetApplicationStatistics(userID uint) (map[string]i
 	// 确保所有状态都有默认值
 	stats[string(model.StatusAccepted)] = stats[string(model.StatusAccepted)]
 	stats[string(model.StatusRejected)] = stats[string(model.StatusRejected)]
+	stats[string(model.StatusGhosted)] = stats[string(model.StatusGhosted)]
 	stats[string(model.StatusInterview)] = stats[string(model.StatusInterview)]
 	stats[string(model.StatusWritten)] = stats[string(model.StatusWritten)]
 	stats[string(model.StatusSubmitted)] = stats[string(model.StatusSubmitted)]
@@ -159,80 +328,48 @@ func (s *ApplicationService) GetApplicationStatistics(userID uint) (map[string]i
 //	return applications, total, nil
 //}
 
-// GetApplicationsWithPagination 获取分页的申请记录
-func (s *ApplicationService) GetApplicationsWithPagination(userID uint, page, pageSize int, searchQuery string, statuses []string) ([]model.Application, int64, error) {
-	var applications []model.Application
+// GetApplicationsWithPagination 获取分页的申请记录（按页码分页，新客户端请使用 ListApplications）
+// 带全文检索条件时结果按相关度排序并附带高亮摘要，命中超过上限时 truncated 为 true，total 只统计上限以内的部分
+func (s *ApplicationService) GetApplicationsWithPagination(p Principal, page, pageSize int, filter ApplicationFilter) ([]model.Application, int64, bool, error) {
 	var total int64
 
-	// 构建基础查询
-	baseQuery := database.DB.Table("applications USE INDEX (idx_user_deleted_status_updated)").
-		Select(
-			"id",
-			"company",
-			"position",
-			"status",
-			"event_link",
-			"updated_at",
-		).
-		Where("user_id = ? AND deleted_at IS NULL", userID)
-
-	// 如果有搜索关键词，添加公司名称搜索条件
-	if searchQuery != "" {
-		baseQuery = baseQuery.Where("company LIKE ?", "%"+searchQuery+"%")
+	if pageSize > MaxListLimit {
+		pageSize = MaxListLimit
 	}
 
-	// 如果有状态筛选，添加状态条件
-	if len(statuses) > 0 {
-		baseQuery = baseQuery.Where("status IN ?", statuses)
+	f, err := filterApplications(p, pagedListTable(), filter)
+	if err != nil {
+		return nil, 0, false, err
 	}
+	if f.empty() {
+		return []model.Application{}, 0, false, nil
+	}
+	baseQuery := f.db
 
 	// 获取总记录数（使用克隆的查询以避免影响主查询）
 	countQuery := baseQuery.Session(&gorm.Session{})
 	if err := countQuery.Count(&total).Error; err != nil {
-		return nil, 0, err
+		return nil, 0, false, err
 	}
 
 	// 获取分页数据（不包含 notes 字段）
 	offset := (page - 1) * pageSize
-	var basicResults []model.Application
-	if err := baseQuery.Order("updated_at DESC").
+	if f.query != nil {
+		baseQuery = baseQuery.Clauses(relevanceOrder(f.hitIDs))
+	} else {
+		baseQuery = baseQuery.Order("updated_at DESC")
+	}
+	var applications []model.Application
+	if err := baseQuery.
 		Limit(pageSize).
 		Offset(offset).
-		Find(&basicResults).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// 如果没有数据，直接返回
-	if len(basicResults) == 0 {
-		return basicResults, total, nil
-	}
-
-	// 获取记录的 ID 列表
-	var ids []uint
-	for _, app := range basicResults {
-		ids = append(ids, app.ID)
-	}
-
-	// 查询这些记录的 notes 字段
-	var notesResults []model.Application
-	if err := database.DB.Model(&model.Application{}).
-		Select("id", "notes").
-		Where("id IN ?", ids).
-		Find(&notesResults).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// 将 notes 字段合并到结果中
-	notesMap := make(map[uint]string)
-	for _, note := range notesResults {
-		notesMap[note.ID] = note.Notes
+		Find(&applications).Error; err != nil {
+		return nil, 0, false, err
 	}
 
-	// 组装最终结果
-	applications = basicResults
-	for i := range applications {
-		applications[i].Notes = notesMap[applications[i].ID]
+	if err := completeListItems(p, applications, f.query); err != nil {
+		return nil, 0, false, err
 	}
 
-	return applications, total, nil
+	return applications, total, f.truncated, nil
 }
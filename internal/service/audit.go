@@ -0,0 +1,175 @@
+package service
+
+import (
+	"encoding/json"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditService struct{}
+
+// AuditQuery 审计日志查询条件
+type AuditQuery struct {
+	ActorID    uint
+	OwnerID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+// ListUserEvents 查询与用户本人数据相关的审计日志
+func (s *AuditService) ListUserEvents(p Principal, page, pageSize int) ([]model.AuditEvent, int64, error) {
+	return s.query(AuditQuery{OwnerID: p.UserID, Page: page, PageSize: pageSize})
+}
+
+// ListAllEvents 管理员查询全部审计日志
+func (s *AuditService) ListAllEvents(p Principal, q AuditQuery) ([]model.AuditEvent, int64, error) {
+	events, total, err := s.query(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := recordAudit(database.DB, p, model.AuditActionAdminViewAudit, model.AuditTargetSystem, 0, 0, nil, nil); err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (s *AuditService) query(q AuditQuery) ([]model.AuditEvent, int64, error) {
+	var events []model.AuditEvent
+	var total int64
+
+	query := database.DB.Model(&model.AuditEvent{})
+	if q.ActorID != 0 {
+		query = query.Where("actor_id = ?", q.ActorID)
+	}
+	if q.OwnerID != 0 {
+		query = query.Where("owner_id = ?", q.OwnerID)
+	}
+	if q.Action != "" {
+		query = query.Where("action = ?", q.Action)
+	}
+	if q.TargetType != "" {
+		query = query.Where("target_type = ?", q.TargetType)
+	}
+	if q.TargetID != 0 {
+		query = query.Where("target_id = ?", q.TargetID)
+	}
+	if q.From != nil {
+		query = query.Where("created_at >= ?", *q.From)
+	}
+	if q.To != nil {
+		query = query.Where("created_at < ?", *q.To)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (q.Page - 1) * q.PageSize
+	if err := query.Order("id DESC").Limit(q.PageSize).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// recordAudit 写入一条审计日志，tx 为当前业务所在的事务
+// before/after 为变更前后的数据，只保存发生变化的字段；新增时 before 为 nil，删除时 after 为 nil
+func recordAudit(tx *gorm.DB, p Principal, action, targetType string, targetID, ownerID uint, before, after interface{}) error {
+	beforeJSON, afterJSON, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.AuditEvent{
+		ActorID:    p.UserID,
+		OwnerID:    ownerID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		IP:         p.IP,
+		RequestID:  p.RequestID,
+	}).Error
+}
+
+// auditDiff 计算前后两份数据的差异字段
+func auditDiff(before, after interface{}) (json.RawMessage, json.RawMessage, error) {
+	beforeMap, err := toAuditMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterMap, err := toAuditMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 忽略时间戳这类每次都会变化的字段
+	for _, key := range []string{"CreatedAt", "UpdatedAt", "DeletedAt", "created_at", "updated_at", "deleted_at"} {
+		delete(beforeMap, key)
+		delete(afterMap, key)
+	}
+
+	if beforeMap != nil && afterMap != nil {
+		for key, value := range beforeMap {
+			if newValue, ok := afterMap[key]; ok && reflect.DeepEqual(value, newValue) {
+				delete(beforeMap, key)
+				delete(afterMap, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditMap(beforeMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalAuditMap(afterMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+// redactUserAudit 账号清除时对审计日志脱敏：用户数据的变更内容和 IP 清空，
+// 用户操作别人数据的记录只清空 IP，变更内容属于数据所属用户，保留。
+// 审计日志的触发器只允许这种更新，操作者、动作、对象等字段保持不变
+func redactUserAudit(tx *gorm.DB, userID uint) error {
+	now := time.Now()
+	if err := tx.Model(&model.AuditEvent{}).Where("owner_id = ?", userID).
+		Updates(map[string]interface{}{"before_data": nil, "after_data": nil, "ip": "", "redacted_at": now}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.AuditEvent{}).Where("actor_id = ? AND owner_id <> ?", userID, userID).
+		Updates(map[string]interface{}{"ip": "", "redacted_at": now}).Error
+}
+
+func toAuditMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func marshalAuditMap(m map[string]interface{}) (json.RawMessage, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
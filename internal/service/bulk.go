@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 批量操作类型
+const (
+	BulkSetStatus = "set_status" // 修改状态
+	BulkAddTag    = "add_tag"    // 添加标签
+	BulkRemoveTag = "remove_tag" // 移除标签
+	BulkDelete    = "delete"     // 移入回收站
+	BulkArchive   = "archive"    // 归档
+	BulkUnarchive = "unarchive"  // 取消归档
+	BulkRestore   = "restore"    // 从回收站恢复
+)
+
+// 单次批量操作的最大条数
+const maxBulkItems = 500
+
+var ErrInvalidBulkRequest = errors.New("批量操作参数错误")
+
+// BulkRequest 批量操作请求
+type BulkRequest struct {
+	IDs    []uint                  `json:"ids" binding:"required,min=1"`
+	Action string                  `json:"action" binding:"required,oneof=set_status add_tag remove_tag delete archive unarchive restore"`
+	Status model.ApplicationStatus `json:"status"` // set_status 时必填
+	Tag    string                  `json:"tag"`    // add_tag/remove_tag 时必填
+}
+
+// BulkItemResult 单条记录的处理结果
+type BulkItemResult struct {
+	ID      uint   `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkUpdate 在一个事务中批量处理申请记录，单条失败只回滚该条，其余照常提交
+func (s *ApplicationService) BulkUpdate(p Principal, req BulkRequest) ([]BulkItemResult, error) {
+	if len(req.IDs) > maxBulkItems {
+		return nil, fmt.Errorf("%w: 单次最多处理%d条", ErrInvalidBulkRequest, maxBulkItems)
+	}
+	switch req.Action {
+	case BulkSetStatus:
+		if !req.Status.Valid() {
+			return nil, ErrInvalidStatus
+		}
+	case BulkAddTag, BulkRemoveTag:
+		if _, err := normalizeTagName(req.Tag); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]BulkItemResult, 0, len(req.IDs))
+	seen := make(map[uint]bool, len(req.IDs))
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range req.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			savepoint := fmt.Sprintf("bulk_item_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+
+			if err := applyBulkAction(tx, p, req, id); err != nil {
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return rbErr
+				}
+				results = append(results, BulkItemResult{ID: id, Error: err.Error()})
+				continue
+			}
+			results = append(results, BulkItemResult{ID: id, Success: true})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// applyBulkAction 对单条申请执行批量操作
+func applyBulkAction(tx *gorm.DB, p Principal, req BulkRequest, id uint) error {
+	if req.Action == BulkRestore {
+		application, err := findOwnedDeletedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		return restoreApplicationTx(tx, p, model.HistorySourceBulk, application)
+	}
+
+	application, err := findOwnedApplication(tx, p, id)
+	if err != nil {
+		return err
+	}
+
+	switch req.Action {
+	case BulkSetStatus:
+		return setStatusTx(tx, p, model.HistorySourceBulk, application, req.Status)
+	case BulkAddTag:
+		return addTagTx(tx, p, model.HistorySourceBulk, application, req.Tag)
+	case BulkRemoveTag:
+		return removeTagTx(tx, p, model.HistorySourceBulk, application, req.Tag)
+	case BulkDelete:
+		return deleteApplicationTx(tx, p, model.HistorySourceBulk, application)
+	case BulkArchive:
+		return setArchivedTx(tx, p, model.HistorySourceBulk, application, true)
+	case BulkUnarchive:
+		return setArchivedTx(tx, p, model.HistorySourceBulk, application, false)
+	}
+	return ErrInvalidBulkRequest
+}
+
+// setArchivedTx 归档或取消归档，调用方需已完成归属校验；状态未变化时不做任何操作
+func setArchivedTx(tx *gorm.DB, p Principal, source string, application *model.Application, archived bool) error {
+	if (application.ArchivedAt != nil) == archived {
+		return nil
+	}
+
+	var archivedAt *time.Time
+	action, auditAction := model.HistoryUnarchive, model.AuditActionApplicationUnarchive
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+		action, auditAction = model.HistoryArchive, model.AuditActionApplicationArchive
+	}
+
+	oldArchivedAt := application.ArchivedAt
+	if err := updateApplicationVersioned(tx, application, map[string]interface{}{"archived_at": archivedAt}); err != nil {
+		return err
+	}
+	if err := recordHistory(tx, p, source, model.ApplicationHistory{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		Action:        action,
+	}); err != nil {
+		return err
+	}
+	return recordAudit(tx, p, auditAction, model.AuditTargetApplication, application.ID, application.UserID,
+		map[string]interface{}{"archived_at": oldArchivedAt}, map[string]interface{}{"archived_at": archivedAt})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"internship-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// VersionConflictError 乐观锁冲突，Current 为数据库中的最新数据，供客户端合并
+type VersionConflictError struct {
+	Version uint
+	Current interface{}
+}
+
+func (e *VersionConflictError) Error() string {
+	return "数据已被修改，请刷新后重试"
+}
+
+// checkVersion 校验客户端携带的版本号（If-Match），expected 为 nil 时不校验
+func checkVersion(expected *uint, current uint, currentData interface{}) error {
+	if expected != nil && *expected != current {
+		return &VersionConflictError{Version: current, Current: currentData}
+	}
+	return nil
+}
+
+// updateApplicationVersioned 按读取时的版本号条件更新申请并递增版本号，
+// 期间被其它请求修改过则返回 VersionConflictError
+func updateApplicationVersioned(tx *gorm.DB, application *model.Application, updates map[string]interface{}) error {
+	if company, ok := updates["company"].(string); ok {
+		updates["company_key"] = NormalizeCompany(company)
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Model(&model.Application{}).
+		Where("id = ? AND version = ?", application.ID, application.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var current model.Application
+		if err := tx.Unscoped().First(&current, application.ID).Error; err != nil {
+			return err
+		}
+		return &VersionConflictError{Version: current.Version, Current: &current}
+	}
+	application.Version++
+	return nil
+}
+
+// updateUserVersioned 按读取时的版本号条件更新用户并递增版本号
+func updateUserVersioned(tx *gorm.DB, user *model.User, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND version = ?", user.ID, user.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var current model.User
+		if err := tx.First(&current, user.ID).Error; err != nil {
+			return err
+		}
+		return &VersionConflictError{Version: current.Version, Current: &current}
+	}
+	user.Version++
+	return nil
+}
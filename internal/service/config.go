@@ -0,0 +1,52 @@
+package service
+
+import (
+	"internship-manager/pkg/pdf"
+	"time"
+)
+
+// 账号清除方式
+const (
+	PurgeModeDelete    = "delete"    // 物理删除
+	PurgeModeAnonymize = "anonymize" // 匿名化保留
+)
+
+// Config 业务层配置，由 main 在启动时通过 InitConfig 注入
+type Config struct {
+	// AppBaseURL 前端访问地址，用于拼接邮件中的链接
+	AppBaseURL string
+
+	// AccountDeletionGrace 注销冷静期，期间可撤销
+	AccountDeletionGrace time.Duration
+	// AccountPurgeMode 冷静期结束后的清除方式：delete 或 anonymize
+	AccountPurgeMode string
+
+	// ExportDir 数据导出文件的存放目录
+	ExportDir string
+	// ExportLinkTTL 导出文件下载链接有效期
+	ExportLinkTTL time.Duration
+
+	// TrashRetention 回收站中的申请记录保留时长，超过后自动永久删除
+	TrashRetention time.Duration
+
+	// InsightsMinUsers 公司统计的最少参与用户数，不足时不返回统计结果
+	InsightsMinUsers int
+
+	// PDFFont 嵌入 PDF 的中文字体，为 nil 时使用不嵌入的 STSong-Light
+	PDFFont *pdf.Font
+}
+
+var config = Config{
+	AppBaseURL:           "http://localhost:8080",
+	AccountDeletionGrace: 14 * 24 * time.Hour,
+	AccountPurgeMode:     PurgeModeDelete,
+	ExportDir:            "exports",
+	ExportLinkTTL:        24 * time.Hour,
+	TrashRetention:       30 * 24 * time.Hour,
+	InsightsMinUsers:     5,
+}
+
+// InitConfig 初始化业务层配置
+func InitConfig(c Config) {
+	config = c
+}
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/utils"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// 职位相似度达到该阈值即视为疑似重复
+	positionSimilarityThreshold = 0.6
+	// 单次最多合并的记录数
+	maxMergeSources = 50
+	// 历史详情字段的长度上限
+	maxHistoryDetail = 255
+)
+
+var ErrInvalidMerge = errors.New("合并参数错误")
+
+var (
+	// 公司名中的括号内容，如"（北京）"
+	companyBracketPattern = regexp.MustCompile(`[（(\[【].*?[)）\]】]`)
+	// 中文公司名常见后缀，中文不以空格分词，按长度从长到短直接匹配末尾
+	companyCJKSuffixes = []string{"股份有限公司", "有限责任公司", "有限公司", "集团", "公司"}
+	// 英文公司名常见后缀，只去除完整的单词，避免 Cisco、Costco 之类的名字被截断
+	companyWordSuffixes = map[string]bool{
+		"incorporated": true, "corporation": true, "limited": true, "company": true,
+		"inc": true, "ltd": true, "llc": true, "corp": true, "co": true,
+	}
+	// 职位名中不影响判断的修饰词
+	positionNoiseWords = []string{"实习生", "实习", "intern", "internship", "日常", "暑期"}
+)
+
+// DuplicateCandidate 疑似重复的申请
+type DuplicateCandidate struct {
+	ID         uint                    `json:"id"`
+	Company    string                  `json:"company"`
+	Position   string                  `json:"position"`
+	Status     model.ApplicationStatus `json:"status"`
+	Similarity float64                 `json:"similarity"`
+}
+
+// DuplicateGroup 一组互相疑似重复的申请
+type DuplicateGroup struct {
+	CompanyKey   string              `json:"company_key"`
+	Applications []model.Application `json:"applications"`
+}
+
+// NormalizeCompany 归一化公司名称：去除括号内容和常见公司后缀，再去除空白和标点。
+// 后缀在按空白和标点分词之后、拼接之前去除，英文后缀必须是完整的单词
+func NormalizeCompany(company string) string {
+	s := strings.ToLower(companyBracketPattern.ReplaceAllString(company, ""))
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for changed := true; changed && len(words) > 0; {
+		changed = false
+		last := words[len(words)-1]
+		if companyWordSuffixes[last] && len(words) > 1 {
+			words = words[:len(words)-1]
+			changed = true
+			continue
+		}
+		for _, suffix := range companyCJKSuffixes {
+			if !strings.HasSuffix(last, suffix) {
+				continue
+			}
+			if last != suffix {
+				words[len(words)-1] = strings.TrimSuffix(last, suffix)
+				changed = true
+			} else if len(words) > 1 {
+				words = words[:len(words)-1]
+				changed = true
+			}
+			break
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// normalizePosition 归一化职位名称
+func normalizePosition(position string) string {
+	s := strings.ToLower(position)
+	for _, word := range positionNoiseWords {
+		s = strings.ReplaceAll(s, word, "")
+	}
+	return stripNonWord(s)
+}
+
+// stripNonWord 去除空白和标点，只保留字母、数字和汉字
+func stripNonWord(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// positionSimilarity 职位相似度，一方包含另一方时视为相同
+func positionSimilarity(a, b string) float64 {
+	na, nb := normalizePosition(a), normalizePosition(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	if strings.Contains(na, nb) || strings.Contains(nb, na) {
+		return 1
+	}
+	return utils.BigramSimilarity(na, nb)
+}
+
+// findDuplicates 查找与给定公司、职位疑似重复的申请，excludeID 为自身ID
+func findDuplicates(tx *gorm.DB, p Principal, company, position string, excludeID uint) ([]DuplicateCandidate, error) {
+	var candidates []model.Application
+	if err := tx.Scopes(ownedApplications(p)).
+		Select("id", "company", "position", "status").
+		Where("company_key = ? AND id <> ?", NormalizeCompany(company), excludeID).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var duplicates []DuplicateCandidate
+	for _, c := range candidates {
+		similarity := positionSimilarity(position, c.Position)
+		if similarity >= positionSimilarityThreshold {
+			duplicates = append(duplicates, DuplicateCandidate{
+				ID:         c.ID,
+				Company:    c.Company,
+				Position:   c.Position,
+				Status:     c.Status,
+				Similarity: similarity,
+			})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Similarity > duplicates[j].Similarity })
+	return duplicates, nil
+}
+
+// ListDuplicateGroups 列出操作者所有疑似重复的申请分组
+func (s *ApplicationService) ListDuplicateGroups(p Principal) ([]DuplicateGroup, error) {
+	var applications []model.Application
+	if err := database.DB.Scopes(ownedApplications(p)).
+		Where("company_key <> ''").
+		Order("company_key ASC, id ASC").
+		Find(&applications).Error; err != nil {
+		return nil, err
+	}
+
+	byCompany := make(map[string][]model.Application)
+	var keys []string
+	for _, app := range applications {
+		if _, ok := byCompany[app.CompanyKey]; !ok {
+			keys = append(keys, app.CompanyKey)
+		}
+		byCompany[app.CompanyKey] = append(byCompany[app.CompanyKey], app)
+	}
+
+	var groups []DuplicateGroup
+	for _, key := range keys {
+		apps := byCompany[key]
+		if len(apps) < 2 {
+			continue
+		}
+
+		// 并查集：职位相似的申请归入同一组
+		parent := make([]int, len(apps))
+		for i := range parent {
+			parent[i] = i
+		}
+		var find func(int) int
+		find = func(i int) int {
+			if parent[i] != i {
+				parent[i] = find(parent[i])
+			}
+			return parent[i]
+		}
+		for i := 0; i < len(apps); i++ {
+			for j := i + 1; j < len(apps); j++ {
+				if positionSimilarity(apps[i].Position, apps[j].Position) >= positionSimilarityThreshold {
+					parent[find(j)] = find(i)
+				}
+			}
+		}
+
+		members := make(map[int][]model.Application)
+		var roots []int
+		for i, app := range apps {
+			root := find(i)
+			if _, ok := members[root]; !ok {
+				roots = append(roots, root)
+			}
+			members[root] = append(members[root], app)
+		}
+		for _, root := range roots {
+			if len(members[root]) > 1 {
+				groups = append(groups, DuplicateGroup{CompanyKey: key, Applications: members[root]})
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// MergeApplications 将 sourceIDs 合并到 targetID：合并备注，迁移历史、标签、日程等关联数据，
+// 合并后永久删除被合并的记录，返回保留下来的记录
+func (s *ApplicationService) MergeApplications(p Principal, targetID uint, sourceIDs []uint) (*model.Application, error) {
+	if len(sourceIDs) == 0 || len(sourceIDs) > maxMergeSources {
+		return nil, ErrInvalidMerge
+	}
+	seen := make(map[uint]bool)
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, fmt.Errorf("%w: 不能与自身合并", ErrInvalidMerge)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: 重复的ID %d", ErrInvalidMerge, id)
+		}
+		seen[id] = true
+	}
+
+	var merged *model.Application
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		target, err := findOwnedApplication(tx, p, targetID)
+		if err != nil {
+			return err
+		}
+		before := *target
+
+		var sources []*model.Application
+		for _, id := range sourceIDs {
+			source, err := findOwnedApplication(tx, p, id)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, source)
+		}
+
+		if err := moveExperience(tx, target.ID, sourceIDs); err != nil {
+			return err
+		}
+
+		notes := []string{target.Notes}
+		eventLink := target.EventLink
+		var mergedFrom []string
+		for _, source := range sources {
+			notes = append(notes, source.Notes)
+			if eventLink == "" {
+				eventLink = source.EventLink
+			}
+			// 只保留目标记录的时间线，被合并记录的新建和状态变更记入合并历史
+			timeline, err := dropTimeline(tx, source.ID)
+			if err != nil {
+				return err
+			}
+			mergedFrom = append(mergedFrom, fmt.Sprintf("#%d（%s）", source.ID, timeline))
+
+			if err := moveApplicationChildren(tx, source.ID, target.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := updateApplicationVersioned(tx, target, map[string]interface{}{
+			"notes":      mergeNotes(notes),
+			"event_link": eventLink,
+		}); err != nil {
+			return err
+		}
+		if err := refreshNextEvent(tx, target.ID); err != nil {
+			return err
+		}
+
+		for _, source := range sources {
+			if err := purgeApplication(tx, p, source); err != nil {
+				return err
+			}
+		}
+
+		if err := recordHistory(tx, p, model.HistorySourceUser, model.ApplicationHistory{
+			ApplicationID: target.ID,
+			UserID:        target.UserID,
+			Action:        model.HistoryMerge,
+			Detail:        truncateRunes("合并自 "+strings.Join(mergedFrom, ", "), maxHistoryDetail),
+		}); err != nil {
+			return err
+		}
+
+		merged, err = findOwnedApplication(tx, p, targetID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionApplicationMerge, model.AuditTargetApplication, target.ID, target.UserID, &before, merged)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeNotes 合并备注，忽略空备注和默认值"无"，去除重复内容
+func mergeNotes(notes []string) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, note := range notes {
+		note = strings.TrimSpace(note)
+		if note == "" || note == "无" || seen[note] {
+			continue
+		}
+		seen[note] = true
+		parts = append(parts, note)
+	}
+	if len(parts) == 0 {
+		return "无"
+	}
+	return strings.Join(parts, "\n---\n")
+}
+
+// dropTimeline 删除申请的新建和状态变更历史，返回其摘要，如"2026-03-01 新建，submitted→interview"
+func dropTimeline(tx *gorm.DB, applicationID uint) (string, error) {
+	var rows []model.ApplicationHistory
+	if err := tx.Where("application_id = ? AND action IN ?", applicationID, []string{model.HistoryCreate, model.HistoryStatus}).
+		Order("created_at ASC, id ASC").
+		Find(&rows).Error; err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "无状态历史", nil
+	}
+
+	var created string
+	var statuses []string
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+		if row.Action == model.HistoryCreate {
+			if created == "" {
+				created = row.CreatedAt.Format("2006-01-02") + " 新建"
+			}
+		} else if len(statuses) == 0 && row.FromStatus != "" {
+			statuses = append(statuses, string(row.FromStatus))
+		}
+		if row.ToStatus != "" && (len(statuses) == 0 || statuses[len(statuses)-1] != string(row.ToStatus)) {
+			statuses = append(statuses, string(row.ToStatus))
+		}
+	}
+	if err := tx.Delete(&model.ApplicationHistory{}, ids).Error; err != nil {
+		return "", err
+	}
+
+	parts := []string{}
+	if created != "" {
+		parts = append(parts, created)
+	}
+	if len(statuses) > 0 {
+		parts = append(parts, strings.Join(statuses, "→"))
+	}
+	return strings.Join(parts, "，"), nil
+}
+
+// moveExperience 将被合并记录发布的面经迁移到 targetID。每条申请只能发布一篇，
+// 目标记录和被合并记录中有多篇时拒绝合并，由用户先删除多余的面经
+func moveExperience(tx *gorm.DB, targetID uint, sourceIDs []uint) error {
+	var experiences []model.Experience
+	if err := tx.Select("id", "application_id").
+		Where("application_id IN ?", append([]uint{targetID}, sourceIDs...)).
+		Find(&experiences).Error; err != nil {
+		return err
+	}
+	if len(experiences) > 1 {
+		return fmt.Errorf("%w: 多条申请都已发布面经，请先删除多余的面经", ErrInvalidMerge)
+	}
+	if len(experiences) == 0 || *experiences[0].ApplicationID == targetID {
+		return nil
+	}
+	return tx.Model(&experiences[0]).Update("application_id", targetID).Error
+}
+
+// truncateRunes 截断到 n 个字符以内，截断时以省略号结尾
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// moveApplicationChildren 将关联数据从 fromID 迁移到 toID
+func moveApplicationChildren(tx *gorm.DB, fromID, toID uint) error {
+	// 标签以 (application_id, tag_id) 为主键，先复制不重复的部分
+	var tagIDs []uint
+	if err := tx.Model(&model.ApplicationTag{}).Where("application_id = ?", fromID).Pluck("tag_id", &tagIDs).Error; err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.ApplicationTag{ApplicationID: toID, TagID: tagID}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, child := range applicationOwnedModels() {
+		if _, ok := child.(*model.ApplicationTag); ok {
+			continue
+		}
+		if err := tx.Model(child).Where("application_id = ?", fromID).Update("application_id", toID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillCompanyKeys 为尚未计算归一化公司名的历史记录补全 company_key，由后台任务调用
+func (s *ApplicationService) BackfillCompanyKeys() error {
+	if err := backfillCompanyKeys(&model.Application{}); err != nil {
+		return err
+	}
+	return backfillCompanyKeys(&model.Experience{})
+}
+
+// backfillCompanyKeys 补全一张表中 company_key 为空的记录
+func backfillCompanyKeys(table interface{}) error {
+	var lastID uint
+	for {
+		var rows []struct {
+			ID      uint
+			Company string
+		}
+		if err := database.DB.Unscoped().Model(table).Select("id", "company").
+			Where("company_key = '' AND id > ?", lastID).
+			Order("id ASC").
+			Limit(500).
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			// 只补全字段，不递增版本号、不改变更新时间，不影响客户端持有的 ETag 和按更新时间的排序
+			if err := database.DB.Unscoped().Model(table).
+				Where("id = ?", row.ID).
+				UpdateColumns(map[string]interface{}{
+					"company_key": NormalizeCompany(row.Company),
+					"updated_at":  gorm.Expr("updated_at"),
+				}).Error; err != nil {
+				return err
+			}
+			lastID = row.ID
+		}
+	}
+}
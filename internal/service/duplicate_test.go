@@ -0,0 +1,25 @@
+package service
+
+import "testing"
+
+func TestNormalizeCompany(t *testing.T) {
+	cases := map[string]string{
+		"Cisco":                       "cisco",
+		"Costco":                      "costco",
+		"Cisco Systems, Inc.":         "ciscosystems",
+		"Costco Wholesale Corp":       "costcowholesale",
+		"Acme Co., Ltd.":              "acme",
+		"ACME Company":                "acme",
+		"Co":                          "co",
+		"字节跳动（北京）有限公司":                "字节跳动",
+		"字节跳动 有限公司":                   "字节跳动",
+		"阿里巴巴集团":                      "阿里巴巴",
+		"腾讯科技(深圳)有限公司":                "腾讯科技",
+		"Bytedance (Beijing) Limited": "bytedance",
+	}
+	for input, want := range cases {
+		if got := NormalizeCompany(input); got != want {
+			t.Errorf("NormalizeCompany(%q) = %q, 期望 %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/mailer"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 修改邮箱验证链接有效期
+const emailChangeTTL = 24 * time.Hour
+
+var (
+	ErrEmailTaken        = errors.New("邮箱已被使用")
+	ErrEmailTokenInvalid = errors.New("验证链接无效或已过期")
+	ErrWrongPassword     = errors.New("密码错误")
+)
+
+// RequestEmailChange 申请修改邮箱，向新邮箱发送验证链接
+func (s *UserService) RequestEmailChange(p Principal, newEmail, password string) error {
+	var user model.User
+	if err := database.DB.First(&user, p.UserID).Error; err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	if newEmail == user.Email {
+		return errors.New("新邮箱与当前邮箱相同")
+	}
+	if err := ensureEmailAvailable(database.DB, newEmail); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := newVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 旧的未使用申请全部作废
+		now := time.Now()
+		if err := tx.Model(&model.EmailChangeRequest{}).
+			Where("user_id = ? AND consumed_at IS NULL", user.ID).
+			Update("consumed_at", &now).Error; err != nil {
+			return err
+		}
+
+		req := model.EmailChangeRequest{
+			UserID:    user.ID,
+			NewEmail:  newEmail,
+			TokenHash: tokenHash,
+			ExpiresAt: now.Add(emailChangeTTL),
+		}
+		if err := tx.Create(&req).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionUserEmailChangeRequest, model.AuditTargetUser, user.ID, user.ID,
+			nil, map[string]interface{}{"new_email": newEmail})
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", config.AppBaseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("你好 %s：\n\n请在24小时内点击以下链接确认新的邮箱地址：\n%s\n\n如果不是你本人操作，请忽略此邮件。", user.Username, link)
+	return mailer.Send(newEmail, "确认新的邮箱地址", body)
+}
+
+// ConfirmEmailChange 验证新邮箱并生效，返回更新后的用户资料
+func (s *UserService) ConfirmEmailChange(p Principal, token string) (*model.User, error) {
+	sum := sha256.Sum256([]byte(token))
+	tokenHash := hex.EncodeToString(sum[:])
+
+	var user model.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var req model.EmailChangeRequest
+		if err := tx.Where("token_hash = ? AND consumed_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+			First(&req).Error; err != nil {
+			return ErrEmailTokenInvalid
+		}
+
+		if err := tx.First(&user, req.UserID).Error; err != nil {
+			return ErrUserNotFound
+		}
+		if err := ensureEmailAvailable(tx, req.NewEmail); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&req).Update("consumed_at", &now).Error; err != nil {
+			return err
+		}
+
+		oldEmail := user.Email
+		if err := updateUserVersioned(tx, &user, map[string]interface{}{"email": req.NewEmail}); err != nil {
+			return err
+		}
+		user.Email = req.NewEmail
+
+		// 通过邮件链接验证时请求可能未登录，操作者记为邮箱所属用户
+		p.UserID = user.ID
+		return recordAudit(tx, p, model.AuditActionUserEmailChange, model.AuditTargetUser, user.ID, user.ID,
+			map[string]interface{}{"email": oldEmail}, map[string]interface{}{"email": req.NewEmail})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// ensureEmailAvailable 检查邮箱是否已被其他账号使用（包括已软删除的账号，唯一索引同样覆盖它们）
+func ensureEmailAvailable(db *gorm.DB, email string) error {
+	var count int64
+	if err := db.Unscoped().Model(&model.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// newVerificationToken 生成随机验证令牌，返回令牌明文及其 SHA-256 摘要
+func newVerificationToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	sum := sha256.Sum256([]byte(token))
+	return token, hex.EncodeToString(sum[:]), nil
+}
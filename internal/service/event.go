@@ -0,0 +1,327 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEventNotFound = errors.New("日程不存在")
+	ErrInvalidEvent  = errors.New("无效的日程类型或时间")
+)
+
+// EventInput 新增日程的参数
+type EventInput struct {
+	Type        model.EventType `json:"type" binding:"required"`
+	Title       string          `json:"title" binding:"max=128"`
+	ScheduledAt time.Time       `json:"scheduled_at" binding:"required"`
+	Link        string          `json:"link" binding:"max=256"`
+	Notes       string          `json:"notes"`
+}
+
+// EventUpdate 修改日程的参数，字段为 nil 表示不修改
+type EventUpdate struct {
+	Type        *model.EventType `json:"type"`
+	Title       *string          `json:"title" binding:"omitempty,max=128"`
+	ScheduledAt *time.Time       `json:"scheduled_at"`
+	Link        *string          `json:"link" binding:"omitempty,max=256"`
+	Notes       *string          `json:"notes"`
+	Done        *bool            `json:"done"` // 标记为已完成或取消完成
+}
+
+// MockInterviewInput 记录不关联申请的模拟面试
+type MockInterviewInput struct {
+	Title       string    `json:"title" binding:"max=128"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Link        string    `json:"link" binding:"max=256"`
+	Notes       string    `json:"notes"`
+}
+
+// UpcomingEvent 即将到来的日程，附带所属申请的公司和职位
+type UpcomingEvent struct {
+	model.ApplicationEvent
+	Company  string `json:"company"`
+	Position string `json:"position"`
+}
+
+// ListEvents 获取某条申请的全部日程，按时间先后排序
+func (s *ApplicationService) ListEvents(p Principal, applicationID uint) ([]model.ApplicationEvent, error) {
+	if _, err := findOwnedApplication(database.DB, p, applicationID); err != nil {
+		return nil, err
+	}
+
+	var events []model.ApplicationEvent
+	if err := database.DB.Where("application_id = ? AND user_id = ?", applicationID, p.UserID).
+		Order("scheduled_at ASC, id ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListUpcomingEvents 获取操作者接下来 within 时长内尚未完成的日程
+func (s *ApplicationService) ListUpcomingEvents(p Principal, within time.Duration) ([]UpcomingEvent, error) {
+	now := time.Now()
+	return pendingEventsBetween(p, now, now.Add(within))
+}
+
+// pendingEventsBetween 获取操作者在 [from, to] 内尚未完成的日程，附带公司和职位
+func pendingEventsBetween(p Principal, from, to time.Time) ([]UpcomingEvent, error) {
+	var events []UpcomingEvent
+	if err := database.DB.Table("application_events AS e").
+		Select("e.*, a.company, a.position").
+		Joins("JOIN applications a ON a.id = e.application_id AND a.deleted_at IS NULL").
+		Where("e.user_id = ? AND e.done_at IS NULL AND e.scheduled_at BETWEEN ? AND ?", p.UserID, from, to).
+		Order("e.scheduled_at ASC, e.id ASC").
+		Scan(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// CreateEvent 为申请新增日程
+func (s *ApplicationService) CreateEvent(p Principal, applicationID uint, input EventInput) (*model.ApplicationEvent, error) {
+	return createEvent(p, &applicationID, input)
+}
+
+// ListMockInterviews 获取操作者不关联申请的模拟面试，按时间先后排序
+func (s *ApplicationService) ListMockInterviews(p Principal) ([]model.ApplicationEvent, error) {
+	var events []model.ApplicationEvent
+	if err := database.DB.Where("user_id = ? AND application_id IS NULL", p.UserID).
+		Order("scheduled_at ASC, id ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// CreateMockInterview 记录一次不关联申请的模拟面试，与申请下的模拟面试日程一样计入目标
+func (s *ApplicationService) CreateMockInterview(p Principal, input MockInterviewInput) (*model.ApplicationEvent, error) {
+	return createEvent(p, nil, EventInput{
+		Type:        model.EventMock,
+		Title:       input.Title,
+		ScheduledAt: input.ScheduledAt,
+		Link:        input.Link,
+		Notes:       input.Notes,
+	})
+}
+
+// UpdateMockInterview 修改不关联申请的模拟面试或标记完成，类型不能改为其他日程
+func (s *ApplicationService) UpdateMockInterview(p Principal, eventID uint, req EventUpdate) (*model.ApplicationEvent, error) {
+	if req.Type != nil && *req.Type != model.EventMock {
+		return nil, ErrInvalidEvent
+	}
+	return updateEvent(p, nil, eventID, req)
+}
+
+// DeleteMockInterview 删除不关联申请的模拟面试
+func (s *ApplicationService) DeleteMockInterview(p Principal, eventID uint) error {
+	return deleteEvent(p, nil, eventID)
+}
+
+// createEvent 新增日程，applicationID 为 nil 时为不关联申请的模拟面试，不写申请历史
+func createEvent(p Principal, applicationID *uint, input EventInput) (*model.ApplicationEvent, error) {
+	if !input.Type.Valid() || input.ScheduledAt.IsZero() || (applicationID == nil && input.Type != model.EventMock) {
+		return nil, ErrInvalidEvent
+	}
+
+	event := model.ApplicationEvent{
+		ApplicationID: applicationID,
+		UserID:        p.UserID,
+		Type:          input.Type,
+		Title:         input.Title,
+		ScheduledAt:   input.ScheduledAt,
+		Link:          input.Link,
+		Notes:         input.Notes,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if applicationID != nil {
+			if _, err := findOwnedApplication(tx, p, *applicationID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		if applicationID != nil {
+			if err := refreshNextEvent(tx, *applicationID); err != nil {
+				return err
+			}
+			if err := recordHistory(tx, p, model.HistorySourceUser, model.ApplicationHistory{
+				ApplicationID: *applicationID,
+				UserID:        p.UserID,
+				Action:        model.HistoryEventAdd,
+				Detail:        string(event.Type) + " " + event.ScheduledAt.Format("2006-01-02 15:04"),
+			}); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, p, model.AuditActionEventCreate, model.AuditTargetEvent, event.ID, p.UserID, nil, &event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// UpdateEvent 修改日程，标记完成时写入申请历史
+func (s *ApplicationService) UpdateEvent(p Principal, applicationID, eventID uint, req EventUpdate) (*model.ApplicationEvent, error) {
+	return updateEvent(p, &applicationID, eventID, req)
+}
+
+// updateEvent 修改日程，applicationID 为 nil 时为不关联申请的模拟面试
+func updateEvent(p Principal, applicationID *uint, eventID uint, req EventUpdate) (*model.ApplicationEvent, error) {
+	updates := make(map[string]interface{})
+	if req.Type != nil {
+		if !req.Type.Valid() {
+			return nil, ErrInvalidEvent
+		}
+		updates["type"] = *req.Type
+	}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.ScheduledAt != nil {
+		if req.ScheduledAt.IsZero() {
+			return nil, ErrInvalidEvent
+		}
+		updates["scheduled_at"] = *req.ScheduledAt
+	}
+	if req.Link != nil {
+		updates["link"] = *req.Link
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	var after model.ApplicationEvent
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findEvent(tx, p, applicationID, eventID)
+		if err != nil {
+			return err
+		}
+
+		markedDone := false
+		if req.Done != nil {
+			switch {
+			case *req.Done && before.DoneAt == nil:
+				updates["done_at"] = time.Now()
+				markedDone = true
+			case !*req.Done && before.DoneAt != nil:
+				updates["done_at"] = nil
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&model.ApplicationEvent{}).Where("id = ?", eventID).Updates(updates).Error; err != nil {
+				return err
+			}
+			if applicationID != nil {
+				if err := refreshNextEvent(tx, *applicationID); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.First(&after, eventID).Error; err != nil {
+			return err
+		}
+
+		if markedDone && applicationID != nil {
+			if err := recordHistory(tx, p, model.HistorySourceUser, model.ApplicationHistory{
+				ApplicationID: *applicationID,
+				UserID:        p.UserID,
+				Action:        model.HistoryEventDone,
+				Detail:        string(after.Type) + " " + after.ScheduledAt.Format("2006-01-02 15:04"),
+			}); err != nil {
+				return err
+			}
+			// 面试结束后提醒发送感谢信、跟进结果
+			if after.Type == model.EventInterview {
+				application, err := findOwnedApplication(tx, p, *applicationID)
+				if err != nil {
+					return err
+				}
+				if err := createAutoTasks(tx, p, application, fmt.Sprintf("event:%d", eventID), time.Now(), interviewDoneTasks); err != nil {
+					return err
+				}
+			}
+		}
+		return recordAudit(tx, p, model.AuditActionEventUpdate, model.AuditTargetEvent, eventID, p.UserID, before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// DeleteEvent 删除日程
+func (s *ApplicationService) DeleteEvent(p Principal, applicationID, eventID uint) error {
+	return deleteEvent(p, &applicationID, eventID)
+}
+
+// deleteEvent 删除日程，applicationID 为 nil 时为不关联申请的模拟面试
+func deleteEvent(p Principal, applicationID *uint, eventID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		event, err := findEvent(tx, p, applicationID, eventID)
+		if err != nil {
+			return err
+		}
+		// 保留面试题，只取消与该日程的关联；需要在删除日程之前，否则违反外键约束
+		if err := tx.Model(&model.InterviewQuestion{}).Where("event_id = ?", eventID).
+			Update("event_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(event).Error; err != nil {
+			return err
+		}
+		if applicationID != nil {
+			if err := refreshNextEvent(tx, *applicationID); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, p, model.AuditActionEventDelete, model.AuditTargetEvent, eventID, p.UserID, event, nil)
+	})
+}
+
+// findOwnedEvent 查找操作者本人某条申请下的日程，申请在回收站中时视为不存在
+func findOwnedEvent(tx *gorm.DB, p Principal, applicationID, eventID uint) (*model.ApplicationEvent, error) {
+	return findEvent(tx, p, &applicationID, eventID)
+}
+
+// findEvent 查找操作者本人的日程，applicationID 为 nil 时只查找不关联申请的模拟面试
+func findEvent(tx *gorm.DB, p Principal, applicationID *uint, eventID uint) (*model.ApplicationEvent, error) {
+	query := tx.Where("id = ? AND user_id = ?", eventID, p.UserID)
+	if applicationID != nil {
+		if _, err := findOwnedApplication(tx, p, *applicationID); err != nil {
+			return nil, err
+		}
+		query = query.Where("application_id = ?", *applicationID)
+	} else {
+		query = query.Where("application_id IS NULL")
+	}
+
+	var event model.ApplicationEvent
+	if err := query.First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// refreshNextEvent 重新计算申请的 next_event_at。该字段由日程派生，不递增版本号，也不改变更新时间
+func refreshNextEvent(tx *gorm.DB, applicationID uint) error {
+	return tx.Exec(`
+		UPDATE applications
+		SET next_event_at = (
+			SELECT MIN(scheduled_at) FROM application_events
+			WHERE application_id = ? AND done_at IS NULL
+		), updated_at = updated_at
+		WHERE id = ?`, applicationID, applicationID).Error
+}
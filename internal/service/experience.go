@@ -0,0 +1,575 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/internal/search"
+	"internship-manager/pkg/database"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	experienceHideThreshold = 3 // 未处理的举报达到该数量时自动隐藏，等待管理员审核
+
+	maxExperienceRounds    = 20
+	maxRoundQuestions      = 50
+	maxExperienceSummary   = 10000
+	maxExperienceRoundNote = 2000
+)
+
+// 面经列表的排序方式
+const (
+	ExperienceSortLatest = "latest" // 最新发布
+	ExperienceSortTop    = "top"    // 点赞最多
+)
+
+// 管理员的审核操作
+const (
+	ModerationApprove = "approve" // 举报不成立，恢复显示
+	ModerationRemove  = "remove"  // 下架
+)
+
+var (
+	ErrExperienceNotFound = errors.New("面经不存在")
+	ErrExperienceExists   = errors.New("该申请已发布过面经")
+	ErrInvalidExperience  = errors.New("无效的面经内容")
+	ErrOwnExperience      = errors.New("不能给自己的面经点赞或举报")
+	ErrAlreadyReported    = errors.New("已举报过该面经")
+	ErrInvalidReport      = errors.New("举报理由不能为空且不超过512个字符")
+	ErrInvalidModeration  = errors.New("无效的审核操作，可选 approve、remove")
+)
+
+// 申请状态对应的面经结果
+var experienceOutcomes = map[model.ApplicationStatus]string{
+	model.StatusAccepted: model.OutcomeOffer,
+	model.StatusRejected: model.OutcomeRejected,
+	model.StatusGhosted:  model.OutcomeNoResponse,
+}
+
+// 面试轮次的名称
+var interviewRoundNames = []string{"一面", "二面", "三面", "四面", "五面"}
+
+type ExperienceService struct{}
+
+// ExperienceInput 发布面经的参数，Rounds、Outcome 为空时由申请的日程、面试题和状态生成
+type ExperienceInput struct {
+	ApplicationID uint                    `json:"application_id" binding:"required"`
+	Position      string                  `json:"position"`
+	Outcome       string                  `json:"outcome"`
+	Rounds        *model.ExperienceRounds `json:"rounds"`
+	Summary       string                  `json:"summary"`
+}
+
+// ExperienceQuery 面经列表的筛选条件
+type ExperienceQuery struct {
+	Company  string // 公司名，按归一化后的名称模糊匹配
+	Position string
+	Sort     string
+	Page     int
+	PageSize int
+}
+
+// ExperienceItem 面经及当前用户与它的关系
+type ExperienceItem struct {
+	model.Experience
+	Upvoted bool `json:"upvoted"`
+	Mine    bool `json:"mine"`
+}
+
+// ReportedExperience 审核队列中的面经及未处理的举报
+type ReportedExperience struct {
+	model.Experience
+	ReportCount int                      `json:"report_count"`
+	Reports     []model.ExperienceReport `json:"reports"`
+}
+
+// DraftExperience 根据申请生成面经草稿，只包含笔试和面试的轮次、月份和题目，不包含日程标题、备注等可能暴露身份的内容
+func (s *ExperienceService) DraftExperience(p Principal, applicationID uint) (*ExperienceInput, error) {
+	_, draft, err := experienceDraft(p, applicationID)
+	return draft, err
+}
+
+func experienceDraft(p Principal, applicationID uint) (*model.Application, *ExperienceInput, error) {
+	application, err := findOwnedApplication(database.DB, p, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []model.ApplicationEvent
+	if err := database.DB.Where("application_id = ? AND type IN ?", applicationID,
+		[]model.EventType{model.EventWritten, model.EventInterview}).
+		Order("scheduled_at ASC, id ASC").
+		Find(&events).Error; err != nil {
+		return nil, nil, err
+	}
+	var questions []model.InterviewQuestion
+	if err := database.DB.Where("application_id = ?", applicationID).Order("id ASC").Find(&questions).Error; err != nil {
+		return nil, nil, err
+	}
+	byEvent := make(map[uint][]string)
+	var unlinked []string
+	for _, q := range questions {
+		if q.EventID != nil {
+			byEvent[*q.EventID] = append(byEvent[*q.EventID], q.Question)
+		} else {
+			unlinked = append(unlinked, q.Question)
+		}
+	}
+
+	rounds := model.ExperienceRounds{}
+	interviews := 0
+	for _, e := range events {
+		round := model.ExperienceRound{
+			Type:      string(e.Type),
+			Month:     e.ScheduledAt.Format("2006-01"),
+			Questions: byEvent[e.ID],
+		}
+		if e.Type == model.EventWritten {
+			round.Name = "笔试"
+		} else {
+			round.Name = interviewRoundName(interviews)
+			interviews++
+		}
+		rounds = append(rounds, round)
+	}
+	if len(unlinked) > 0 {
+		rounds = append(rounds, model.ExperienceRound{Name: "其他", Type: string(model.EventOther), Questions: unlinked})
+	}
+
+	outcome, ok := experienceOutcomes[application.Status]
+	if !ok {
+		outcome = model.OutcomeInProgress
+	}
+	return application, &ExperienceInput{
+		ApplicationID: applicationID,
+		Position:      application.Position,
+		Outcome:       outcome,
+		Rounds:        &rounds,
+	}, nil
+}
+
+func interviewRoundName(i int) string {
+	if i < len(interviewRoundNames) {
+		return interviewRoundNames[i]
+	}
+	return fmt.Sprintf("第%d轮面试", i+1)
+}
+
+// PublishExperience 从操作者的申请发布匿名面经，每条申请只能发布一篇
+func (s *ExperienceService) PublishExperience(p Principal, input ExperienceInput) (*model.Experience, error) {
+	application, draft, err := experienceDraft(p, input.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Rounds == nil {
+		input.Rounds = draft.Rounds
+	}
+	if input.Outcome == "" {
+		input.Outcome = draft.Outcome
+	}
+	if input.Position = strings.TrimSpace(input.Position); input.Position == "" {
+		input.Position = draft.Position
+	}
+	if err := validateExperience(&input); err != nil {
+		return nil, err
+	}
+
+	experience := model.Experience{
+		UserID:        p.UserID,
+		ApplicationID: &application.ID,
+		Company:       application.Company,
+		CompanyKey:    NormalizeCompany(application.Company),
+		Position:      input.Position,
+		Outcome:       input.Outcome,
+		Rounds:        *input.Rounds,
+		Summary:       input.Summary,
+		Status:        model.ExperiencePublished,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Experience{}).Where("application_id = ?", application.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrExperienceExists
+		}
+		if err := tx.Create(&experience).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionExperiencePublish, model.AuditTargetExperience, experience.ID, p.UserID, nil, &experience)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &experience, nil
+}
+
+// validateExperience 校验结果、轮次和长度限制，并去除空白题目
+func validateExperience(input *ExperienceInput) error {
+	switch input.Outcome {
+	case model.OutcomeOffer, model.OutcomeRejected, model.OutcomeNoResponse, model.OutcomeInProgress:
+	default:
+		return ErrInvalidExperience
+	}
+	if utf8.RuneCountInString(input.Position) > 128 || utf8.RuneCountInString(input.Summary) > maxExperienceSummary {
+		return ErrInvalidExperience
+	}
+
+	rounds := *input.Rounds
+	if len(rounds) > maxExperienceRounds {
+		return ErrInvalidExperience
+	}
+	for i := range rounds {
+		round := &rounds[i]
+		round.Name = strings.TrimSpace(round.Name)
+		if round.Name == "" || utf8.RuneCountInString(round.Name) > 32 ||
+			!model.EventType(round.Type).Valid() || model.EventType(round.Type) == model.EventMock ||
+			len(round.Questions) > maxRoundQuestions || utf8.RuneCountInString(round.Notes) > maxExperienceRoundNote {
+			return ErrInvalidExperience
+		}
+		if round.Month != "" {
+			if _, err := time.Parse("2006-01", round.Month); err != nil {
+				return ErrInvalidExperience
+			}
+		}
+		questions := round.Questions[:0]
+		for _, q := range round.Questions {
+			if q = strings.TrimSpace(q); q == "" {
+				continue
+			}
+			if utf8.RuneCountInString(q) > maxQuestionLength {
+				return ErrInvalidExperience
+			}
+			questions = append(questions, q)
+		}
+		round.Questions = questions
+	}
+	return nil
+}
+
+// ListExperiences 查询公开的面经
+func (s *ExperienceService) ListExperiences(p Principal, q ExperienceQuery) ([]ExperienceItem, int64, error) {
+	db := database.DB.Model(&model.Experience{}).Where("status = ?", model.ExperiencePublished)
+	if q.Company != "" {
+		db = db.Where("company_key LIKE ?", "%"+search.EscapeLike(NormalizeCompany(q.Company))+"%")
+	}
+	if q.Position != "" {
+		db = db.Where("position LIKE ?", "%"+search.EscapeLike(q.Position)+"%")
+	}
+
+	var order string
+	switch q.Sort {
+	case "", ExperienceSortLatest:
+		order = "id DESC"
+	case ExperienceSortTop:
+		order = "upvotes DESC, id DESC"
+	default:
+		return nil, 0, ErrInvalidSort
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var experiences []model.Experience
+	if err := db.Order(order).Limit(q.PageSize).Offset((q.Page - 1) * q.PageSize).Find(&experiences).Error; err != nil {
+		return nil, 0, err
+	}
+	items, err := experienceItems(p, experiences)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListMyExperiences 获取操作者发布的全部面经，包含被隐藏和下架的
+func (s *ExperienceService) ListMyExperiences(p Principal) ([]ExperienceItem, error) {
+	var experiences []model.Experience
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id DESC").Find(&experiences).Error; err != nil {
+		return nil, err
+	}
+	return experienceItems(p, experiences)
+}
+
+// GetExperience 获取单篇面经，未公开的只有作者可以查看
+func (s *ExperienceService) GetExperience(p Principal, id uint) (*ExperienceItem, error) {
+	experience, err := findVisibleExperience(database.DB, p, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := experienceItems(p, []model.Experience{*experience})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// experienceItems 标记当前用户点赞过的和自己发布的面经
+func experienceItems(p Principal, experiences []model.Experience) ([]ExperienceItem, error) {
+	items := make([]ExperienceItem, len(experiences))
+	if len(experiences) == 0 {
+		return items, nil
+	}
+
+	ids := make([]uint, len(experiences))
+	for i, e := range experiences {
+		ids[i] = e.ID
+	}
+	var upvoted []uint
+	if err := database.DB.Model(&model.ExperienceVote{}).
+		Where("user_id = ? AND experience_id IN ?", p.UserID, ids).
+		Pluck("experience_id", &upvoted).Error; err != nil {
+		return nil, err
+	}
+	upvotedSet := make(map[uint]bool, len(upvoted))
+	for _, id := range upvoted {
+		upvotedSet[id] = true
+	}
+
+	for i, e := range experiences {
+		items[i] = ExperienceItem{Experience: e, Upvoted: upvotedSet[e.ID], Mine: e.UserID == p.UserID}
+	}
+	return items, nil
+}
+
+// findVisibleExperience 查找操作者可以查看的面经：公开的，或自己发布的
+func findVisibleExperience(tx *gorm.DB, p Principal, id uint) (*model.Experience, error) {
+	var experience model.Experience
+	if err := tx.Where("id = ? AND (status = ? OR user_id = ?)", id, model.ExperiencePublished, p.UserID).
+		First(&experience).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExperienceNotFound
+		}
+		return nil, err
+	}
+	return &experience, nil
+}
+
+// DeleteExperience 作者撤回面经，点赞和举报一并删除
+func (s *ExperienceService) DeleteExperience(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var experience model.Experience
+		if err := tx.Where("id = ? AND user_id = ?", id, p.UserID).First(&experience).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrExperienceNotFound
+			}
+			return err
+		}
+		if err := deleteExperiences(tx, []uint{id}); err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionExperienceDelete, model.AuditTargetExperience, id, p.UserID, &experience, nil)
+	})
+}
+
+// deleteExperiences 删除面经及其点赞和举报
+func deleteExperiences(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("experience_id IN ?", ids).Delete(&model.ExperienceVote{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("experience_id IN ?", ids).Delete(&model.ExperienceReport{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&model.Experience{}, ids).Error
+}
+
+// SetUpvote 点赞或取消点赞，重复操作不报错
+func (s *ExperienceService) SetUpvote(p Principal, id uint, upvote bool) (*model.Experience, error) {
+	var experience *model.Experience
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		experience, err = findVisibleExperience(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if experience.UserID == p.UserID {
+			return ErrOwnExperience
+		}
+
+		var result *gorm.DB
+		delta := 1
+		if upvote {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&model.ExperienceVote{ExperienceID: id, UserID: p.UserID})
+		} else {
+			result = tx.Where("experience_id = ? AND user_id = ?", id, p.UserID).Delete(&model.ExperienceVote{})
+			delta = -1
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&model.Experience{}).Where("id = ?", id).
+			UpdateColumn("upvotes", gorm.Expr("upvotes + ?", delta)).Error; err != nil {
+			return err
+		}
+		experience.Upvotes += delta
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return experience, nil
+}
+
+// ReportExperience 举报面经，未处理的举报达到阈值时自动隐藏
+func (s *ExperienceService) ReportExperience(p Principal, id uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > 512 {
+		return ErrInvalidReport
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		experience, err := findVisibleExperience(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if experience.UserID == p.UserID {
+			return ErrOwnExperience
+		}
+
+		report := model.ExperienceReport{ExperienceID: id, UserID: p.UserID, Reason: reason}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyReported
+		}
+
+		updates := map[string]interface{}{"report_count": gorm.Expr("report_count + 1")}
+		if experience.Status == model.ExperiencePublished && experience.ReportCount+1 >= experienceHideThreshold {
+			updates["status"] = model.ExperienceHidden
+		}
+		if err := tx.Model(&model.Experience{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		// 审计日志归属举报人，避免作者从操作记录中看到举报人
+		return recordAudit(tx, p, model.AuditActionExperienceReport, model.AuditTargetExperience, id, p.UserID, nil, &report)
+	})
+}
+
+// ListReportedExperiences 管理员查询审核队列：有未处理举报的面经，被自动隐藏的在前
+func (s *ExperienceService) ListReportedExperiences(p Principal, page, pageSize int) ([]ReportedExperience, int64, error) {
+	db := database.DB.Model(&model.Experience{}).Where("report_count > 0 AND status <> ?", model.ExperienceRemoved)
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var experiences []model.Experience
+	if err := db.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:  "status = ? DESC, report_count DESC, id ASC",
+		Vars: []interface{}{model.ExperienceHidden},
+	}}).Limit(pageSize).Offset((page - 1) * pageSize).Find(&experiences).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]ReportedExperience, len(experiences))
+	ids := make([]uint, len(experiences))
+	for i, e := range experiences {
+		items[i] = ReportedExperience{Experience: e, ReportCount: e.ReportCount, Reports: []model.ExperienceReport{}}
+		ids[i] = e.ID
+	}
+	if len(ids) > 0 {
+		var reports []model.ExperienceReport
+		if err := database.DB.Where("experience_id IN ? AND resolved_at IS NULL", ids).
+			Order("id ASC").Find(&reports).Error; err != nil {
+			return nil, 0, err
+		}
+		byExperience := make(map[uint][]model.ExperienceReport)
+		for _, r := range reports {
+			byExperience[r.ExperienceID] = append(byExperience[r.ExperienceID], r)
+		}
+		for i := range items {
+			if reports, ok := byExperience[items[i].ID]; ok {
+				items[i].Reports = reports
+			}
+		}
+	}
+
+	if err := recordAudit(database.DB, p, model.AuditActionAdminViewReports, model.AuditTargetSystem, 0, 0, nil, nil); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ModerateExperience 管理员处理举报：approve 恢复显示，remove 下架；未处理的举报全部标记为已处理
+func (s *ExperienceService) ModerateExperience(p Principal, id uint, action string) (*model.Experience, error) {
+	var status string
+	switch action {
+	case ModerationApprove:
+		status = model.ExperiencePublished
+	case ModerationRemove:
+		status = model.ExperienceRemoved
+	default:
+		return nil, ErrInvalidModeration
+	}
+
+	var after model.Experience
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var before model.Experience
+		if err := tx.First(&before, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrExperienceNotFound
+			}
+			return err
+		}
+		if err := tx.Model(&model.ExperienceReport{}).
+			Where("experience_id = ? AND resolved_at IS NULL", id).
+			Update("resolved_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Experience{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"status": status, "report_count": 0}).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionAdminModerate, model.AuditTargetExperience, id, before.UserID,
+			map[string]interface{}{"status": before.Status, "report_count": before.ReportCount},
+			map[string]interface{}{"status": after.Status, "report_count": after.ReportCount, "action": action})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// purgeUserExperiences 清除账号时删除其发布的面经，以及其点赞（同时扣减点赞数）和举报
+func purgeUserExperiences(tx *gorm.DB, userID uint) error {
+	var ids []uint
+	if err := tx.Model(&model.Experience{}).Where("user_id = ?", userID).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if err := deleteExperiences(tx, ids); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.Experience{}).
+		Where("id IN (?)", tx.Model(&model.ExperienceVote{}).Select("experience_id").Where("user_id = ?", userID)).
+		UpdateColumn("upvotes", gorm.Expr("upvotes - 1")).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&model.ExperienceVote{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&model.Experience{}).
+		Where("id IN (?)", tx.Model(&model.ExperienceReport{}).Select("experience_id").Where("user_id = ? AND resolved_at IS NULL", userID)).
+		UpdateColumn("report_count", gorm.Expr("report_count - 1")).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userID).Delete(&model.ExperienceReport{}).Error
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/mailer"
+	"internship-manager/pkg/utils"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 导出文件格式版本，manifest 中记录
+const exportFormatVersion = 1
+
+var (
+	ErrExportNotFound = errors.New("导出任务不存在")
+	ErrExportExpired  = errors.New("下载链接无效或已过期")
+	ErrExportRunning  = errors.New("已有导出任务正在进行")
+)
+
+// UserDataExport 用户全部个人数据
+type UserDataExport struct {
+	ExportedAt    time.Time                  `json:"exported_at"`
+	Profile       *model.User                `json:"profile"`
+	Applications  []model.Application        `json:"applications"`
+	History       []model.ApplicationHistory `json:"history"`
+	Events        []model.ApplicationEvent   `json:"events"`
+	Tags          []model.Tag                `json:"tags"`
+	Views         []model.SavedView          `json:"views"`
+	Tasks         []model.Task               `json:"tasks"`
+	Questions     []model.InterviewQuestion  `json:"questions"`
+	Experiences   []model.Experience         `json:"experiences"`
+	Goals         []model.Goal               `json:"goals"`
+	Notifications []model.Notification       `json:"notifications"`
+	StaleRules    []model.StaleRule          `json:"stale_rules"`
+	Digests       []model.DigestSubscription `json:"digest_subscriptions"`
+	AuditEvents   []model.AuditEvent         `json:"audit_events"`
+}
+
+// exportDataset 导出包中的一类数据
+type exportDataset struct {
+	Name    string      // 文件名（不含扩展名）
+	Records interface{} // 结构体切片
+}
+
+// datasets 导出包中包含的数据集，新增用户数据表时在这里追加
+func (e *UserDataExport) datasets() []exportDataset {
+	return []exportDataset{
+		{Name: "profile", Records: []model.User{*e.Profile}},
+		{Name: "applications", Records: e.Applications},
+		{Name: "history", Records: e.History},
+		{Name: "events", Records: e.Events},
+		{Name: "tags", Records: e.Tags},
+		{Name: "views", Records: e.Views},
+		{Name: "tasks", Records: e.Tasks},
+		{Name: "questions", Records: e.Questions},
+		{Name: "experiences", Records: e.Experiences},
+		{Name: "goals", Records: e.Goals},
+		{Name: "notifications", Records: e.Notifications},
+		{Name: "stale_rules", Records: e.StaleRules},
+		{Name: "digest_subscriptions", Records: e.Digests},
+		{Name: "audit_events", Records: e.AuditEvents},
+	}
+}
+
+// ExportUserData 导出操作者本人的全部个人数据
+func (s *UserService) ExportUserData(p Principal) (*UserDataExport, error) {
+	profile, err := s.GetUserByID(p, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := (&ApplicationService{}).ListAllApplications(p)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &UserDataExport{
+		ExportedAt:   time.Now(),
+		Profile:      profile,
+		Applications: applications,
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.History).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Events).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Tags).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Views).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Questions).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Experiences).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Goals).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&export.Notifications).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Find(&export.StaleRules).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("user_id = ?", p.UserID).Find(&export.Digests).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("owner_id = ?", p.UserID).Order("id ASC").Find(&export.AuditEvents).Error; err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// StartExport 创建异步导出任务，生成完成后通过邮件发送下载链接
+func (s *UserService) StartExport(p Principal) (*model.ExportJob, error) {
+	var running int64
+	if err := database.DB.Model(&model.ExportJob{}).
+		Where("user_id = ? AND status IN ?", p.UserID, []model.ExportJobStatus{model.ExportPending, model.ExportRunning}).
+		Count(&running).Error; err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, ErrExportRunning
+	}
+
+	job := model.ExportJob{
+		UserID: p.UserID,
+		Status: model.ExportPending,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionUserExport, model.AuditTargetUser, p.UserID, p.UserID, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.runExport(p, job.ID)
+	return &job, nil
+}
+
+// GetExport 查询导出任务状态
+func (s *UserService) GetExport(p Principal, id uint) (*model.ExportJob, error) {
+	var job model.ExportJob
+	if err := database.DB.Where("id = ? AND user_id = ?", id, p.UserID).First(&job).Error; err != nil {
+		return nil, ErrExportNotFound
+	}
+	return &job, nil
+}
+
+// OpenExportDownload 根据下载令牌查找导出文件，数据库中只保存令牌的摘要
+func (s *UserService) OpenExportDownload(token string) (*model.ExportJob, error) {
+	sum := sha256.Sum256([]byte(token))
+	tokenHash := hex.EncodeToString(sum[:])
+
+	var job model.ExportJob
+	if err := database.DB.Where("download_token_hash = ? AND status = ? AND expires_at > ?", tokenHash, model.ExportDone, time.Now()).
+		First(&job).Error; err != nil {
+		return nil, ErrExportExpired
+	}
+	return &job, nil
+}
+
+// runExport 生成导出包
+func (s *UserService) runExport(p Principal, jobID uint) {
+	if err := database.DB.Model(&model.ExportJob{}).Where("id = ?", jobID).
+		Update("status", model.ExportRunning).Error; err != nil {
+		log.Printf("导出任务 %d 启动失败: %v", jobID, err)
+		return
+	}
+
+	path, size, err := s.buildExportArchive(p, jobID)
+	if err != nil {
+		log.Printf("导出任务 %d 失败: %v", jobID, err)
+		database.DB.Model(&model.ExportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+			"status": model.ExportFailed,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	token, tokenHash, err := newVerificationToken()
+	if err != nil {
+		log.Printf("导出任务 %d 生成下载令牌失败: %v", jobID, err)
+		os.Remove(path)
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(config.ExportLinkTTL)
+	if err := database.DB.Model(&model.ExportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":              model.ExportDone,
+		"file_path":           path,
+		"file_size":           size,
+		"download_token_hash": tokenHash,
+		"completed_at":        &now,
+		"expires_at":          &expiresAt,
+	}).Error; err != nil {
+		log.Printf("导出任务 %d 更新状态失败: %v", jobID, err)
+		os.Remove(path)
+		return
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, p.UserID).Error; err == nil {
+		link := fmt.Sprintf("%s/api/export/download/%s", config.AppBaseURL, token)
+		body := fmt.Sprintf("你好 %s：\n\n你申请的个人数据导出已生成，请在 %s 之前下载：\n%s",
+			user.Username, expiresAt.Format("2006-01-02 15:04"), link)
+		if err := mailer.Send(user.Email, "个人数据导出已完成", body); err != nil {
+			log.Printf("导出任务 %d 发送邮件失败: %v", jobID, err)
+		}
+	}
+}
+
+// exportManifestFile manifest 中的文件条目
+type exportManifestFile struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+// buildExportArchive 生成ZIP导出包，每类数据同时提供JSON和CSV，并附带 manifest.json
+func (s *UserService) buildExportArchive(p Principal, jobID uint) (path string, size int64, err error) {
+	export, err := s.ExportUserData(p)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := os.MkdirAll(config.ExportDir, 0o700); err != nil {
+		return "", 0, err
+	}
+	archivePath := filepath.Join(config.ExportDir, fmt.Sprintf("export-%d-%d.zip", p.UserID, jobID))
+
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	// 生成失败时删除不完整的导出包，失败的任务不会再被清理
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(archivePath)
+		}
+	}()
+
+	archive := zip.NewWriter(file)
+	var files []exportManifestFile
+
+	writeEntry := func(name string, data []byte, records int) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		files = append(files, exportManifestFile{Name: name, Records: records, SHA256: hex.EncodeToString(sum[:])})
+		return nil
+	}
+
+	for _, ds := range export.datasets() {
+		records := sliceLen(ds.Records)
+
+		jsonData, err := json.MarshalIndent(ds.Records, "", "  ")
+		if err != nil {
+			return "", 0, err
+		}
+		if err := writeEntry(ds.Name+".json", jsonData, records); err != nil {
+			return "", 0, err
+		}
+
+		var csvData bytes.Buffer
+		csvData.WriteString("\ufeff") // 让 Excel 正确识别 UTF-8
+		if err := utils.WriteStructsCSV(&csvData, ds.Records); err != nil {
+			return "", 0, err
+		}
+		if err := writeEntry(ds.Name+".csv", csvData.Bytes(), records); err != nil {
+			return "", 0, err
+		}
+	}
+
+	manifest, err := json.MarshalIndent(map[string]interface{}{
+		"format_version": exportFormatVersion,
+		"user_id":        p.UserID,
+		"generated_at":   export.ExportedAt,
+		"files":          files,
+	}, "", "  ")
+	if err != nil {
+		return "", 0, err
+	}
+	w, err := archive.Create("manifest.json")
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := w.Write(manifest); err != nil {
+		return "", 0, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	return archivePath, info.Size(), nil
+}
+
+// CleanupExports 删除过期的导出文件，并将异常中断的任务标记为失败
+func (s *UserService) CleanupExports() error {
+	var expired []model.ExportJob
+	if err := database.DB.Where("status = ? AND expires_at <= ?", model.ExportDone, time.Now()).
+		Find(&expired).Error; err != nil {
+		return err
+	}
+	for _, job := range expired {
+		if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("删除导出文件 %s 失败: %v", job.FilePath, err)
+			continue
+		}
+		database.DB.Model(&job).Updates(map[string]interface{}{"status": model.ExportExpired, "file_path": ""})
+	}
+
+	// 服务重启会中断正在生成的任务
+	return database.DB.Model(&model.ExportJob{}).
+		Where("status IN ? AND updated_at <= ?", []model.ExportJobStatus{model.ExportPending, model.ExportRunning}, time.Now().Add(-time.Hour)).
+		Updates(map[string]interface{}{"status": model.ExportFailed, "error": "任务超时"}).Error
+}
+
+func sliceLen(v interface{}) int {
+	return reflect.ValueOf(v).Len()
+}
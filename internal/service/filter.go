@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"internship-manager/internal/filter"
+	"internship-manager/internal/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 日期条件对应的列
+var filterDateColumns = map[string]string{
+	filter.FieldCreated: "applications.created_at",
+	filter.FieldUpdated: "applications.updated_at",
+	filter.FieldEvent:   "applications.next_event_at",
+}
+
+// applyFilterExpr 将高级筛选表达式转换为查询条件
+func applyFilterExpr(db *gorm.DB, p Principal, expr string) (*gorm.DB, error) {
+	f, err := filter.Parse(expr, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range f.Clauses {
+		sql, args, err := filterClauseSQL(p, c)
+		if err != nil {
+			return nil, err
+		}
+		if c.Negate {
+			// next_event_at 为空时取反条件也应成立
+			if c.Field == filter.FieldEvent {
+				sql = "(applications.next_event_at IS NULL OR NOT (" + sql + "))"
+			} else {
+				sql = "NOT (" + sql + ")"
+			}
+		}
+		db = db.Where(sql, args...)
+	}
+	return db, nil
+}
+
+func filterClauseSQL(p Principal, c filter.Clause) (string, []interface{}, error) {
+	switch c.Field {
+	case filter.FieldStatus:
+		for _, v := range c.Values {
+			if !model.ApplicationStatus(v).Valid() {
+				return "", nil, fmt.Errorf("%w: 未知状态 %s", filter.ErrInvalidFilter, v)
+			}
+		}
+		return "applications.status IN ?", []interface{}{c.Values}, nil
+
+	case filter.FieldTag:
+		names := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			name, err := normalizeTagName(v)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: %v", filter.ErrInvalidFilter, err)
+			}
+			names = append(names, name)
+		}
+		return `EXISTS (SELECT 1 FROM application_tags JOIN tags ON tags.id = application_tags.tag_id
+			WHERE application_tags.application_id = applications.id AND tags.user_id = ? AND tags.name IN ?)`,
+			[]interface{}{p.UserID, names}, nil
+
+	case filter.FieldCompany:
+		keys := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			keys = append(keys, NormalizeCompany(v))
+		}
+		return "applications.company_key IN ?", []interface{}{keys}, nil
+
+	case filter.FieldResume:
+		return "applications.resume_version IN ?", []interface{}{c.Values}, nil
+
+	case filter.FieldHas:
+		var conds []string
+		for _, v := range c.Values {
+			switch v {
+			case filter.HasEvent:
+				conds = append(conds, "applications.next_event_at IS NOT NULL")
+			case filter.HasTag:
+				conds = append(conds, "EXISTS (SELECT 1 FROM application_tags WHERE application_tags.application_id = applications.id)")
+			}
+		}
+		return "(" + strings.Join(conds, " OR ") + ")", nil, nil
+
+	case filter.FieldCreated, filter.FieldUpdated, filter.FieldEvent:
+		column := filterDateColumns[c.Field]
+		var conds []string
+		var args []interface{}
+		if c.From != nil {
+			conds = append(conds, column+" >= ?")
+			args = append(args, *c.From)
+		}
+		if c.To != nil {
+			conds = append(conds, column+" < ?")
+			args = append(args, *c.To)
+		}
+		return "(" + strings.Join(conds, " AND ") + ")", args, nil
+	}
+	return "", nil, fmt.Errorf("%w: 未知字段 %s", filter.ErrInvalidFilter, c.Field)
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxGoalsPerUser = 20
+	maxGoalTarget   = 1000
+)
+
+// 计算连续达成次数时最多回溯的周期数
+var goalLookback = map[string]int{
+	IntervalDay:   365,
+	IntervalWeek:  104,
+	IntervalMonth: 36,
+}
+
+var (
+	ErrGoalNotFound = errors.New("目标不存在")
+	ErrInvalidGoal  = errors.New("无效的目标")
+	ErrGoalLimit    = errors.New("目标数量已达上限")
+)
+
+// 目标指标的显示名称
+var goalMetricNames = map[model.GoalMetric]string{
+	model.GoalApplications:   "投递",
+	model.GoalInterviews:     "面试",
+	model.GoalMockInterviews: "模拟面试",
+	model.GoalEvents:         "日程",
+}
+
+var goalPeriodNames = map[string]string{
+	IntervalDay:   "每天",
+	IntervalWeek:  "每周",
+	IntervalMonth: "每月",
+}
+
+type GoalService struct{}
+
+// GoalInput 新建或修改目标的参数
+type GoalInput struct {
+	Title    string           `json:"title"`
+	Metric   model.GoalMetric `json:"metric" binding:"required"`
+	Period   string           `json:"period"` // 默认 week
+	Target   int              `json:"target" binding:"required"`
+	Timezone string           `json:"timezone"`
+	Active   *bool            `json:"active"` // 默认 true
+}
+
+// GoalProgress 目标在当前周期的进度和连续达成情况
+type GoalProgress struct {
+	Goal          model.Goal `json:"goal"`
+	PeriodStart   time.Time  `json:"period_start"`
+	PeriodEnd     time.Time  `json:"period_end"` // 不包含
+	Current       int        `json:"current"`
+	Target        int        `json:"target"`
+	Achieved      bool       `json:"achieved"`
+	Percent       float64    `json:"percent"`        // 完成比例，可超过 100
+	Streak        int        `json:"streak"`         // 截至当前连续达成的周期数，当前周期尚未达成时不中断
+	LongestStreak int        `json:"longest_streak"` // 最长连续达成的周期数
+}
+
+// ListGoals 获取操作者的全部目标
+func (s *GoalService) ListGoals(p Principal) ([]model.Goal, error) {
+	var goals []model.Goal
+	if err := database.DB.Where("user_id = ?", p.UserID).Order("id ASC").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// CreateGoal 新建目标
+func (s *GoalService) CreateGoal(p Principal, input GoalInput) (*model.Goal, error) {
+	goal := model.Goal{UserID: p.UserID, Active: true}
+	if err := applyGoalInput(&goal, input); err != nil {
+		return nil, err
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Goal{}).Where("user_id = ?", p.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxGoalsPerUser {
+			return ErrGoalLimit
+		}
+		if err := tx.Create(&goal).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionGoalCreate, model.AuditTargetGoal, goal.ID, p.UserID, nil, &goal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// UpdateGoal 修改目标
+func (s *GoalService) UpdateGoal(p Principal, id uint, input GoalInput) (*model.Goal, error) {
+	var after model.Goal
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedGoal(tx, p, id)
+		if err != nil {
+			return err
+		}
+		after = *before
+		if err := applyGoalInput(&after, input); err != nil {
+			return err
+		}
+		// 指标或周期变化后重新判断是否达成
+		if after.Metric != before.Metric || after.Period != before.Period || after.Target != before.Target {
+			after.LastAchievedPeriod = ""
+		}
+		if err := tx.Save(&after).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionGoalUpdate, model.AuditTargetGoal, id, p.UserID, before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// DeleteGoal 删除目标
+func (s *GoalService) DeleteGoal(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		goal, err := findOwnedGoal(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(goal).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionGoalDelete, model.AuditTargetGoal, id, p.UserID, goal, nil)
+	})
+}
+
+func findOwnedGoal(tx *gorm.DB, p Principal, id uint) (*model.Goal, error) {
+	var goal model.Goal
+	if err := tx.Where("id = ? AND user_id = ?", id, p.UserID).First(&goal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// applyGoalInput 校验参数并写入目标
+func applyGoalInput(goal *model.Goal, input GoalInput) error {
+	if input.Period == "" {
+		input.Period = IntervalWeek
+	}
+	if !input.Metric.Valid() || input.Target < 1 || input.Target > maxGoalTarget ||
+		utf8.RuneCountInString(input.Title) > 64 {
+		return ErrInvalidGoal
+	}
+	if _, ok := goalLookback[input.Period]; !ok {
+		return ErrInvalidGoal
+	}
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return ErrInvalidGoal
+		}
+	}
+
+	goal.Title = input.Title
+	goal.Metric = input.Metric
+	goal.Period = input.Period
+	goal.Target = input.Target
+	goal.Timezone = input.Timezone
+	if input.Active != nil {
+		goal.Active = *input.Active
+	}
+	return nil
+}
+
+// goalName 目标的显示名称，未设置标题时由指标和周期生成，如“每周投递10次”
+func goalName(goal *model.Goal) string {
+	if goal.Title != "" {
+		return goal.Title
+	}
+	return fmt.Sprintf("%s%s%d次", goalPeriodNames[goal.Period], goalMetricNames[goal.Metric], goal.Target)
+}
+
+// GetProgress 计算操作者全部启用目标的当前进度
+func (s *GoalService) GetProgress(p Principal) ([]GoalProgress, error) {
+	var goals []model.Goal
+	if err := database.DB.Where("user_id = ? AND active = ?", p.UserID, true).Order("id ASC").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	progress := make([]GoalProgress, 0, len(goals))
+	for i := range goals {
+		item, err := goalProgress(&goals[i], now)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, *item)
+	}
+	return progress, nil
+}
+
+// goalProgress 按目标所在时区计算 now 所在周期的进度，以及从目标创建的周期起的连续达成次数
+func goalProgress(goal *model.Goal, now time.Time) (*GoalProgress, error) {
+	loc := subscriptionLocation(goal.Timezone)
+	start := bucketStart(now, goal.Period, loc)
+	end := nextBucket(start, goal.Period)
+
+	from := bucketStart(goal.CreatedAt, goal.Period, loc)
+	earliest := start
+	for i := 0; i < goalLookback[goal.Period]; i++ {
+		earliest = prevBucket(earliest, goal.Period)
+	}
+	if from.Before(earliest) {
+		from = earliest
+	}
+
+	times, err := goalMetricTimes(goal, from, end)
+	if err != nil {
+		return nil, err
+	}
+	buckets := bucketize(times, goal.Period, loc, from, end)
+
+	current := buckets[len(buckets)-1].Count
+	progress := &GoalProgress{
+		Goal:        *goal,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Current:     current,
+		Target:      goal.Target,
+		Achieved:    current >= goal.Target,
+		Percent:     float64(current) * 100 / float64(goal.Target),
+	}
+
+	run := 0
+	for i, bucket := range buckets {
+		if bucket.Count >= goal.Target {
+			run++
+		} else if i < len(buckets)-1 {
+			run = 0
+		}
+		if run > progress.LongestStreak {
+			progress.LongestStreak = run
+		}
+	}
+	progress.Streak = run
+	return progress, nil
+}
+
+// goalMetricTimes 查询目标指标在 [from, to) 内发生的时间点。日程以计划时间计，只统计已完成的
+func goalMetricTimes(goal *model.Goal, from, to time.Time) ([]time.Time, error) {
+	var times []time.Time
+	if goal.Metric == model.GoalApplications {
+		err := database.DB.Model(&model.Application{}).
+			Where("user_id = ? AND created_at >= ? AND created_at < ?", goal.UserID, from, to).
+			Pluck("created_at", &times).Error
+		return times, err
+	}
+
+	db := database.DB.Model(&model.ApplicationEvent{}).
+		Where("user_id = ? AND done_at IS NOT NULL AND scheduled_at >= ? AND scheduled_at < ?", goal.UserID, from, to)
+	switch goal.Metric {
+	case model.GoalInterviews:
+		db = db.Where("type = ?", model.EventInterview)
+	case model.GoalMockInterviews:
+		db = db.Where("type = ?", model.EventMock)
+	}
+	err := db.Pluck("scheduled_at", &times).Error
+	return times, err
+}
+
+// NotifyAchievedGoals 检查启用的目标，当前周期首次达成时发送通知，由后台任务调用。
+// 与任务提醒一样跳过被禁用和申请注销的用户
+func (s *GoalService) NotifyAchievedGoals() error {
+	var goals []model.Goal
+	if err := database.DB.
+		Joins("JOIN users ON users.id = goals.user_id AND users.disabled = ? AND users.deletion_requested_at IS NULL", false).
+		Where("goals.active = ?", true).
+		Find(&goals).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range goals {
+		goal := &goals[i]
+		// 单个目标计算失败不影响其他目标
+		progress, err := goalProgress(goal, now)
+		if err != nil {
+			log.Printf("计算目标 %d 的进度失败: %v", goal.ID, err)
+			continue
+		}
+		period := progress.PeriodStart.Format("2006-01-02")
+		if !progress.Achieved || goal.LastAchievedPeriod == period {
+			continue
+		}
+
+		body := fmt.Sprintf("本周期已完成 %d / %d", progress.Current, progress.Target)
+		if progress.Streak > 1 {
+			body += fmt.Sprintf("，已连续达成 %d 个周期", progress.Streak)
+		}
+		if err := notify(goal.UserID, model.NotificationGoalAchieved, "目标达成："+goalName(goal), body); err != nil {
+			log.Printf("目标 %d 达成通知失败: %v", goal.ID, err)
+			continue
+		}
+		if err := database.DB.Model(&model.Goal{}).Where("id = ?", goal.ID).
+			UpdateColumn("last_achieved_period", period).Error; err != nil {
+			log.Printf("记录目标 %d 的达成周期失败: %v", goal.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// GetApplicationHistory 获取某条申请的变更历史
+func (s *ApplicationService) GetApplicationHistory(p Principal, id uint) ([]model.ApplicationHistory, error) {
+	if _, err := findOwnedApplication(database.DB.Unscoped(), p, id); err != nil {
+		return nil, err
+	}
+
+	var history []model.ApplicationHistory
+	if err := database.DB.Where("application_id = ? AND user_id = ?", id, p.UserID).
+		Order("id ASC").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// recordHistory 写入一条申请变更历史，调用方填写申请、动作和状态字段
+func recordHistory(tx *gorm.DB, p Principal, source string, h model.ApplicationHistory) error {
+	h.ActorID = p.UserID
+	h.Source = source
+	return tx.Create(&h).Error
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/internal/search"
+	"internship-manager/pkg/database"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxCompanyList = 50
+	// 参与用户数的下限，配置得更小时也不低于该值，避免统计结果对应到单个用户
+	insightsMinUsersFloor = 2
+)
+
+var ErrInvalidCompany = errors.New("无效的公司名称")
+
+type InsightService struct{}
+
+// CompanySummary 有足够参与用户、可以查看统计的公司
+type CompanySummary struct {
+	ID           string `json:"id"` // 归一化后的公司名，用于查询统计
+	Name         string `json:"name"`
+	Contributors int    `json:"contributors"`
+}
+
+// CompanyInsights 汇总所有参与统计的用户的申请得到的公司统计，参与用户不足时 Available 为 false 且不返回数据
+type CompanyInsights struct {
+	ID           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	Available    bool   `json:"available"`
+	MinUsers     int    `json:"min_users"`
+	Contributors int    `json:"contributors,omitempty"` // 参与统计的用户数
+	Applications int    `json:"applications,omitempty"`
+
+	// ResponseRate 收到回复（用户记录的第一次状态变化，系统自动标记的无回复不算）的申请比例
+	ResponseRate *float64 `json:"response_rate,omitempty"`
+	// MedianResponseDays 从投递到收到回复的天数中位数，有回复的用户不足时不返回
+	MedianResponseDays *float64 `json:"median_response_days,omitempty"`
+	// MedianDaysToInterview 从投递到进入面试的天数中位数
+	MedianDaysToInterview *float64 `json:"median_days_to_interview,omitempty"`
+	WrittenRate           *float64 `json:"written_rate,omitempty"`   // 到达笔试（或更后阶段）的比例
+	InterviewRate         *float64 `json:"interview_rate,omitempty"` // 到达面试（或更后阶段）的比例
+	OfferRate             *float64 `json:"offer_rate,omitempty"`     // 录用的比例
+	RejectedRate          *float64 `json:"rejected_rate,omitempty"`  // 当前为已拒绝的比例
+	GhostedRate           *float64 `json:"ghosted_rate,omitempty"`   // 当前为无回复的比例
+}
+
+// insightApplication 参与统计的申请
+type insightApplication struct {
+	ID        uint
+	UserID    uint
+	Company   string
+	Status    model.ApplicationStatus
+	CreatedAt time.Time
+}
+
+// insightHistory 参与统计的状态历史
+type insightHistory struct {
+	ApplicationID uint
+	Action        string
+	Source        string
+	ToStatus      model.ApplicationStatus
+	CreatedAt     time.Time
+}
+
+func insightsMinUsers() int {
+	if config.InsightsMinUsers < insightsMinUsersFloor {
+		return insightsMinUsersFloor
+	}
+	return config.InsightsMinUsers
+}
+
+// insightApplications 参与统计的申请：未删除，且所属用户未注销、未选择退出统计
+func insightApplications() *gorm.DB {
+	return database.DB.Model(&model.Application{}).
+		Joins("JOIN users ON users.id = applications.user_id AND users.deleted_at IS NULL AND users.insights_opt_out = ?", false).
+		Where("applications.company_key <> ''")
+}
+
+// ListCompanies 查询参与用户数达到下限的公司，q 按归一化后的公司名模糊匹配
+func (s *InsightService) ListCompanies(p Principal, q string) ([]CompanySummary, error) {
+	db := insightApplications().
+		Select("applications.company_key AS id, MIN(applications.company) AS name, COUNT(DISTINCT applications.user_id) AS contributors").
+		Group("applications.company_key").
+		Having("COUNT(DISTINCT applications.user_id) >= ?", insightsMinUsers())
+	if q != "" {
+		db = db.Where("applications.company_key LIKE ?", "%"+search.EscapeLike(NormalizeCompany(q))+"%")
+	}
+
+	companies := []CompanySummary{}
+	if err := db.Order("contributors DESC, id ASC").Limit(maxCompanyList).Scan(&companies).Error; err != nil {
+		return nil, err
+	}
+	return companies, nil
+}
+
+// GetCompanyInsights 计算公司的回复时间和各阶段比例。company 可以是公司名或 ListCompanies 返回的 id
+func (s *InsightService) GetCompanyInsights(p Principal, company string) (*CompanyInsights, error) {
+	key := NormalizeCompany(company)
+	if key == "" {
+		return nil, ErrInvalidCompany
+	}
+	minUsers := insightsMinUsers()
+	insights := &CompanyInsights{ID: key, MinUsers: minUsers}
+
+	var applications []insightApplication
+	if err := insightApplications().
+		Select("applications.id, applications.user_id, applications.company, applications.status, applications.created_at").
+		Where("applications.company_key = ?", key).
+		Scan(&applications).Error; err != nil {
+		return nil, err
+	}
+	users := make(map[uint]bool)
+	names := make(map[string]int)
+	for _, app := range applications {
+		users[app.UserID] = true
+		names[app.Company]++
+	}
+	if len(users) < minUsers {
+		return insights, nil
+	}
+
+	// 状态历史按同样的条件连接申请表，不把申请 id 逐个传给数据库
+	var rows []insightHistory
+	if err := database.DB.Model(&model.ApplicationHistory{}).
+		Select("application_histories.application_id, application_histories.action, application_histories.source, application_histories.to_status, application_histories.created_at").
+		Joins("JOIN applications ON applications.id = application_histories.application_id AND applications.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = applications.user_id AND users.deleted_at IS NULL AND users.insights_opt_out = ?", false).
+		Where("applications.company_key = ?", key).
+		Where("application_histories.action IN ? AND application_histories.to_status <> ''", []string{model.HistoryCreate, model.HistoryStatus}).
+		Order("application_histories.application_id ASC, application_histories.created_at ASC, application_histories.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	history := make(map[uint][]insightHistory)
+	for _, row := range rows {
+		history[row.ApplicationID] = append(history[row.ApplicationID], row)
+	}
+
+	stageIndex := make(map[model.ApplicationStatus]int)
+	for i, status := range funnelStages {
+		stageIndex[status] = i
+	}
+	interviewStage := stageIndex[model.StatusInterview]
+
+	var responded, written, interviewed, offered, rejected, ghosted int
+	var responseDays, interviewDays []float64
+	responseUsers := make(map[uint]bool)
+	interviewUsers := make(map[uint]bool)
+	for _, app := range applications {
+		submittedAt := app.CreatedAt
+		furthest := stageIndex[app.Status]
+		var respondedAt, interviewAt *time.Time
+		for i, change := range history[app.ID] {
+			if change.Action == model.HistoryCreate {
+				submittedAt = change.CreatedAt
+				continue
+			}
+			if stage, ok := stageIndex[change.ToStatus]; ok && stage > furthest {
+				furthest = stage
+			}
+			if respondedAt == nil && change.Source != model.HistorySourceSystem && change.ToStatus != model.StatusSubmitted {
+				respondedAt = &history[app.ID][i].CreatedAt
+			}
+			if interviewAt == nil && change.ToStatus == model.StatusInterview {
+				interviewAt = &history[app.ID][i].CreatedAt
+			}
+		}
+
+		if respondedAt != nil {
+			responded++
+			responseUsers[app.UserID] = true
+			responseDays = append(responseDays, respondedAt.Sub(submittedAt).Hours()/24)
+		}
+		if interviewAt != nil {
+			interviewUsers[app.UserID] = true
+			interviewDays = append(interviewDays, interviewAt.Sub(submittedAt).Hours()/24)
+		}
+		if furthest >= stageIndex[model.StatusWritten] {
+			written++
+		}
+		if furthest >= interviewStage {
+			interviewed++
+		}
+		switch app.Status {
+		case model.StatusAccepted:
+			offered++
+		case model.StatusRejected:
+			rejected++
+		case model.StatusGhosted:
+			ghosted++
+		}
+	}
+
+	total := float64(len(applications))
+	rate := func(n int) *float64 {
+		r := float64(n) / total
+		return &r
+	}
+	insights.Available = true
+	insights.Name = mostCommonName(names)
+	insights.Contributors = len(users)
+	insights.Applications = len(applications)
+	insights.ResponseRate = rate(responded)
+	insights.WrittenRate = rate(written)
+	insights.InterviewRate = rate(interviewed)
+	insights.OfferRate = rate(offered)
+	insights.RejectedRate = rate(rejected)
+	insights.GhostedRate = rate(ghosted)
+	// 时长只在样本来自足够多的用户时返回
+	if len(responseUsers) >= minUsers {
+		insights.MedianResponseDays = median(responseDays)
+	}
+	if len(interviewUsers) >= minUsers {
+		insights.MedianDaysToInterview = median(interviewDays)
+	}
+	return insights, nil
+}
+
+// mostCommonName 出现次数最多的公司名写法，次数相同时取字典序最小的
+func mostCommonName(names map[string]int) string {
+	candidates := make([]string, 0, len(names))
+	for name := range names {
+		candidates = append(candidates, name)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if names[candidates[i]] != names[candidates[j]] {
+			return names[candidates[i]] > names[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
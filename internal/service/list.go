@@ -0,0 +1,420 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/internal/search"
+	"internship-manager/pkg/database"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 列表排序字段
+const (
+	SortUpdatedAt = "updated_at" // 最近更新（默认）
+	SortCreatedAt = "created_at" // 创建时间
+	SortCompany   = "company"    // 公司名称
+	SortStatus    = "status"     // 状态在流程中的先后
+	SortNextEvent = "next_event" // 最近的日程时间，没有日程的排在最后
+
+	// sortRelevance 搜索且未指定排序时按相关度排序
+	sortRelevance = "relevance"
+)
+
+// 游标分页每页条数
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("无效的分页游标")
+	ErrInvalidSort   = errors.New("无效的排序字段")
+)
+
+// 排序字段对应的列，每个排序都以 id 作为次要排序，保证顺序稳定
+var sortColumns = map[string]string{
+	SortUpdatedAt: "updated_at",
+	SortCreatedAt: "created_at",
+	SortCompany:   "company",
+	SortStatus:    "status_rank",
+	SortNextEvent: "next_event_at",
+}
+
+// 列表查询的字段，notes 单独查询
+var listColumns = []string{
+	"id",
+	"company",
+	"position",
+	"status",
+	"status_rank",
+	"event_link",
+	"resume_version",
+	"archived_at",
+	"next_event_at",
+	"version",
+	"created_at",
+	"updated_at",
+}
+
+// ApplicationFilter 申请列表的筛选条件
+type ApplicationFilter struct {
+	Search   string   // 全文检索条件
+	Filter   string   // 高级筛选表达式，语法见 internal/filter
+	Statuses []string // 状态
+	Archived bool     // true 只看已归档，false 只看未归档
+}
+
+// ListOptions 游标分页参数
+type ListOptions struct {
+	ApplicationFilter
+	Sort   string // 排序字段，为空时默认按更新时间，搜索时默认按相关度
+	Desc   bool   // 是否倒序
+	Cursor string // 上一页返回的 next_cursor
+	Limit  int    // 每页条数，不超过 MaxListLimit
+}
+
+// ApplicationPage 一页申请记录
+type ApplicationPage struct {
+	Applications []model.Application `json:"applications"`
+	NextCursor   string              `json:"next_cursor,omitempty"` // 为空表示没有下一页
+	Truncated    bool                `json:"truncated,omitempty"`   // 搜索命中超过上限，只能翻阅相关度最高的部分
+}
+
+// listCursor 游标内容，编码后对客户端不透明
+type listCursor struct {
+	Sort   string      `json:"s"`
+	Desc   bool        `json:"d"`
+	Value  interface{} `json:"v,omitempty"` // 上一页最后一条记录的排序值，为空表示已进入空值部分
+	ID     uint        `json:"i,omitempty"` // 上一页最后一条记录的ID
+	Offset int         `json:"o,omitempty"` // 按相关度排序时的偏移量
+}
+
+// filteredApplications 带筛选条件的申请查询
+type filteredApplications struct {
+	db     *gorm.DB
+	query  *search.Query // 搜索条件，未搜索时为 nil
+	hitIDs []uint        // 按相关度排序的命中ID
+	// truncated 命中超过 maxSearchHits 条，hitIDs 只包含相关度最高的部分
+	truncated bool
+}
+
+// empty 搜索没有命中任何记录
+func (f *filteredApplications) empty() bool {
+	return f.query != nil && len(f.hitIDs) == 0
+}
+
+// filterApplications 按筛选条件构建查询，table 可带索引提示
+func filterApplications(p Principal, table string, filter ApplicationFilter) (*filteredApplications, error) {
+	f := &filteredApplications{}
+	if filter.Search != "" {
+		query, err := search.Parse(filter.Search)
+		if err != nil {
+			return nil, err
+		}
+		if f.hitIDs, f.truncated, err = searchApplicationIDs(p, query); err != nil {
+			return nil, err
+		}
+		f.query = &query
+		// 搜索时按命中的ID过滤，不强制使用列表索引
+		table = "applications"
+	}
+
+	db := database.DB.Table(table).
+		Select(listColumns).
+		Scopes(ownedApplications(p)).
+		Where("deleted_at IS NULL")
+
+	if f.query != nil {
+		db = db.Where("id IN ?", f.hitIDs)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Archived {
+		db = db.Where("archived_at IS NOT NULL")
+	} else {
+		db = db.Where("archived_at IS NULL")
+	}
+	if filter.Filter != "" {
+		var err error
+		if db, err = applyFilterExpr(db, p, filter.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	f.db = db
+	return f, nil
+}
+
+// relevanceOrder 保持检索后端给出的相关度顺序。使用标准 SQL 的 CASE 而不是 MySQL 的 FIELD，
+// 内嵌检索后端搭配其他数据库时同样可用
+func relevanceOrder(hitIDs []uint) clause.OrderBy {
+	var sql strings.Builder
+	vars := make([]interface{}, len(hitIDs))
+	sql.WriteString("CASE id")
+	for i, id := range hitIDs {
+		sql.WriteString(" WHEN ? THEN " + strconv.Itoa(i))
+		vars[i] = id
+	}
+	sql.WriteString(" END")
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql.String(), Vars: vars, WithoutParentheses: true}}
+}
+
+// pagedListTable 按页码分页的列表查询所用的表，MySQL 下强制使用列表索引，其他数据库不支持该索引提示
+func pagedListTable() string {
+	if database.DB.Dialector.Name() == "mysql" {
+		return "applications USE INDEX (idx_user_deleted_status_updated)"
+	}
+	return "applications"
+}
+
+// completeListItems 补全列表记录的备注、标签和搜索高亮
+func completeListItems(p Principal, applications []model.Application, query *search.Query) error {
+	if len(applications) == 0 {
+		return nil
+	}
+
+	// 获取记录的 ID 列表
+	var ids []uint
+	for _, app := range applications {
+		ids = append(ids, app.ID)
+	}
+
+	// 查询这些记录的 notes 字段
+	var notesResults []model.Application
+	if err := database.DB.Model(&model.Application{}).
+		Scopes(ownedApplications(p)).
+		Select("id", "notes").
+		Where("id IN ?", ids).
+		Find(&notesResults).Error; err != nil {
+		return err
+	}
+
+	// 将 notes 字段合并到结果中
+	notesMap := make(map[uint]string)
+	for _, note := range notesResults {
+		notesMap[note.ID] = note.Notes
+	}
+	for i := range applications {
+		applications[i].Notes = notesMap[applications[i].ID]
+	}
+
+	if err := attachTags(applications); err != nil {
+		return err
+	}
+	if query != nil {
+		attachHighlights(applications, *query)
+	}
+	return nil
+}
+
+// ListApplications 按游标分页获取申请记录
+func (s *ApplicationService) ListApplications(p Principal, opts ListOptions) (*ApplicationPage, error) {
+	if opts.Limit < 1 {
+		opts.Limit = DefaultListLimit
+	}
+	if opts.Limit > MaxListLimit {
+		opts.Limit = MaxListLimit
+	}
+	if opts.Sort == "" {
+		opts.Sort = SortUpdatedAt
+		if opts.Search != "" {
+			opts.Sort = sortRelevance
+		}
+	}
+	if _, ok := sortColumns[opts.Sort]; !ok && opts.Sort != sortRelevance {
+		return nil, ErrInvalidSort
+	}
+	if opts.Sort == sortRelevance && opts.Search == "" {
+		return nil, ErrInvalidSort
+	}
+
+	cursor, err := decodeCursor(opts.Cursor, opts.Sort, opts.Desc)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := filterApplications(p, "applications", opts.ApplicationFilter)
+	if err != nil {
+		return nil, err
+	}
+	page := &ApplicationPage{Applications: []model.Application{}, Truncated: f.truncated}
+	if f.empty() {
+		return page, nil
+	}
+
+	// 多取一条用于判断是否还有下一页
+	var rows []model.Application
+	if opts.Sort == sortRelevance {
+		rows, err = listByRelevance(f, cursor, opts.Limit+1)
+	} else {
+		rows, err = listByKeyset(f, opts, cursor, opts.Limit+1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) > opts.Limit {
+		rows = rows[:opts.Limit]
+		next := nextCursor(opts, cursor, rows)
+		if page.NextCursor, err = encodeCursor(next); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := completeListItems(p, rows, f.query); err != nil {
+		return nil, err
+	}
+	page.Applications = rows
+	return page, nil
+}
+
+// listByRelevance 按相关度排序，命中结果数量有上限，直接按偏移量分页
+func listByRelevance(f *filteredApplications, cursor *listCursor, limit int) ([]model.Application, error) {
+	offset := 0
+	if cursor != nil {
+		offset = cursor.Offset
+	}
+	var rows []model.Application
+	err := f.db.Clauses(relevanceOrder(f.hitIDs)).Limit(limit).Offset(offset).Find(&rows).Error
+	return rows, err
+}
+
+// listByKeyset 按排序字段和 id 做键集分页。next_event_at 可能为空，
+// 先取有值的部分，不足一页时再按 id 取空值部分，两部分都能用上索引
+func listByKeyset(f *filteredApplications, opts ListOptions, cursor *listCursor, limit int) ([]model.Application, error) {
+	column := sortColumns[opts.Sort]
+	dir, op := "ASC", ">"
+	if opts.Desc {
+		dir, op = "DESC", "<"
+	}
+	nullable := opts.Sort == SortNextEvent
+
+	var rows []model.Application
+	inNullPart := cursor != nil && cursor.Value == nil
+	if !inNullPart {
+		q := f.db.Session(&gorm.Session{})
+		if nullable {
+			q = q.Where(column + " IS NOT NULL")
+		}
+		if cursor != nil {
+			value, err := cursorValue(opts.Sort, cursor.Value)
+			if err != nil {
+				return nil, err
+			}
+			q = q.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))", value, value, cursor.ID)
+		}
+		if err := q.Order(column + " " + dir).Order("id " + dir).Limit(limit).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if nullable && len(rows) < limit {
+		q := f.db.Session(&gorm.Session{}).Where(column + " IS NULL")
+		if inNullPart {
+			q = q.Where("id "+op+" ?", cursor.ID)
+		}
+		var nullRows []model.Application
+		if err := q.Order("id " + dir).Limit(limit - len(rows)).Find(&nullRows).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, nullRows...)
+	}
+	return rows, nil
+}
+
+// nextCursor 根据本页最后一条记录生成下一页游标
+func nextCursor(opts ListOptions, cursor *listCursor, rows []model.Application) listCursor {
+	next := listCursor{Sort: opts.Sort, Desc: opts.Desc}
+	if opts.Sort == sortRelevance {
+		if cursor != nil {
+			next.Offset = cursor.Offset
+		}
+		next.Offset += len(rows)
+		return next
+	}
+
+	last := rows[len(rows)-1]
+	next.ID = last.ID
+	switch opts.Sort {
+	case SortUpdatedAt:
+		next.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case SortCreatedAt:
+		next.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case SortCompany:
+		next.Value = last.Company
+	case SortStatus:
+		next.Value = last.StatusRank
+	case SortNextEvent:
+		if last.NextEventAt != nil {
+			next.Value = last.NextEventAt.Format(time.RFC3339Nano)
+		}
+	}
+	return next
+}
+
+// cursorValue 将游标中的排序值还原为查询参数
+func cursorValue(sort string, value interface{}) (interface{}, error) {
+	switch sort {
+	case SortUpdatedAt, SortCreatedAt, SortNextEvent:
+		s, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	case SortCompany:
+		s, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		return s, nil
+	case SortStatus:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		return int(n), nil
+	}
+	return nil, ErrInvalidCursor
+}
+
+func encodeCursor(c listCursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor 解析游标，游标必须与本次请求的排序方式一致
+func decodeCursor(raw, sort string, desc bool) (*listCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sort || c.Desc != desc {
+		return nil, ErrInvalidCursor
+	}
+	if c.Value == nil && sort != SortNextEvent && sort != sortRelevance {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
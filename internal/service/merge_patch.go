@@ -0,0 +1,181 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidPatch = errors.New("无效的合并补丁")
+
+// ValidationError 字段校验失败
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// validateApplication 校验申请字段，新增和修改共用同一套规则
+func validateApplication(application *model.Application) error {
+	rules := []struct {
+		field    string
+		value    string
+		required bool
+		maxLen   int
+	}{
+		{"company", application.Company, true, 128},
+		{"position", application.Position, true, 128},
+		{"event_link", application.EventLink, false, 256},
+		{"resume_version", application.ResumeVersion, false, 64},
+	}
+	for _, r := range rules {
+		if r.required && strings.TrimSpace(r.value) == "" {
+			return &ValidationError{Field: r.field, Message: "不能为空"}
+		}
+		if utf8.RuneCountInString(r.value) > r.maxLen {
+			return &ValidationError{Field: r.field, Message: fmt.Sprintf("不能超过%d个字符", r.maxLen)}
+		}
+	}
+	if !application.Status.Valid() {
+		return &ValidationError{Field: "status", Message: ErrInvalidStatus.Error()}
+	}
+	return nil
+}
+
+// applyApplicationUpdates 将整体更新的字段应用到申请上，用于写入前校验
+func applyApplicationUpdates(application *model.Application, updates map[string]interface{}) error {
+	for key, value := range updates {
+		s, ok := value.(string)
+		if !ok {
+			return &ValidationError{Field: key, Message: "必须是字符串"}
+		}
+		switch key {
+		case "company":
+			application.Company = s
+		case "position":
+			application.Position = s
+		case "event_link":
+			application.EventLink = s
+		case "notes":
+			application.Notes = s
+		case "resume_version":
+			application.ResumeVersion = s
+		default:
+			return &ValidationError{Field: key, Message: "不允许修改"}
+		}
+	}
+	return nil
+}
+
+// PatchApplication 按 RFC 7396 JSON Merge Patch 部分更新申请：
+// 未出现的字段保持不变，值为 null 的字段被清空，返回更新后的记录
+func (s *ApplicationService) PatchApplication(p Principal, id uint, patch []byte, expectedVersion *uint) (*model.Application, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, ErrInvalidPatch
+	}
+
+	var after *model.Application
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(expectedVersion, before.Version, before); err != nil {
+			return err
+		}
+
+		patched := *before
+		if err := applyApplicationPatch(&patched, fields); err != nil {
+			return err
+		}
+		if err := validateApplication(&patched); err != nil {
+			return err
+		}
+
+		// 状态变更走 setStatusTx，保证写入状态历史
+		current := *before
+		if patched.Status != before.Status {
+			if err := setStatusTx(tx, p, model.HistorySourceUser, &current, patched.Status); err != nil {
+				return err
+			}
+		}
+
+		updates := make(map[string]interface{})
+		if patched.Company != before.Company {
+			updates["company"] = patched.Company
+		}
+		if patched.Position != before.Position {
+			updates["position"] = patched.Position
+		}
+		if patched.EventLink != before.EventLink {
+			updates["event_link"] = patched.EventLink
+		}
+		if patched.Notes != before.Notes {
+			updates["notes"] = patched.Notes
+		}
+		if patched.ResumeVersion != before.ResumeVersion {
+			updates["resume_version"] = patched.ResumeVersion
+		}
+		if len(updates) > 0 {
+			if err := updateApplicationVersioned(tx, &current, updates); err != nil {
+				return err
+			}
+		}
+
+		after, err = findOwnedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return recordAudit(tx, p, model.AuditActionApplicationUpdate, model.AuditTargetApplication, id, before.UserID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// applyApplicationPatch 将合并补丁应用到申请上，只允许修改公开的可编辑字段
+func applyApplicationPatch(application *model.Application, fields map[string]json.RawMessage) error {
+	for key, raw := range fields {
+		var target *string
+		switch key {
+		case "company":
+			target = &application.Company
+		case "position":
+			target = &application.Position
+		case "event_link":
+			target = &application.EventLink
+		case "notes":
+			target = &application.Notes
+		case "resume_version":
+			target = &application.ResumeVersion
+		case "status":
+			target = (*string)(&application.Status)
+		default:
+			return &ValidationError{Field: key, Message: "不允许修改"}
+		}
+
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			*target = ""
+			continue
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return &ValidationError{Field: key, Message: "必须是字符串"}
+		}
+	}
+	return nil
+}
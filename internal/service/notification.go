@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/mailer"
+	"internship-manager/pkg/webhook"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 通知列表最多返回的条数
+const maxNotifications = 100
+
+var (
+	ErrNotificationNotFound       = errors.New("通知不存在")
+	ErrInvalidNotificationSetting = errors.New("无效的通知推送设置")
+)
+
+type NotificationService struct{}
+
+// NotificationSettingInput 修改通知推送方式的参数
+type NotificationSettingInput struct {
+	Channel    string `json:"channel" binding:"required"`
+	WebhookURL string `json:"webhook_url" binding:"max=512"`
+	// RotateSecret 重新生成 webhook 签名密钥，旧密钥立即失效
+	RotateSecret bool `json:"rotate_secret"`
+}
+
+// ListNotifications 获取操作者最近的通知，unreadOnly 为 true 时只返回未读的
+func (s *NotificationService) ListNotifications(p Principal, unreadOnly bool) ([]model.Notification, int64, error) {
+	var unread int64
+	if err := database.DB.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", p.UserID).
+		Count(&unread).Error; err != nil {
+		return nil, 0, err
+	}
+
+	db := database.DB.Where("user_id = ?", p.UserID)
+	if unreadOnly {
+		db = db.Where("read_at IS NULL")
+	}
+	var notifications []model.Notification
+	if err := db.Order("id DESC").Limit(maxNotifications).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+// MarkRead 标记通知为已读
+func (s *NotificationService) MarkRead(p Principal, id uint) error {
+	result := database.DB.Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, p.UserID).
+		Where("read_at IS NULL").
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := database.DB.Model(&model.Notification{}).Where("id = ? AND user_id = ?", id, p.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNotificationNotFound
+		}
+	}
+	return nil
+}
+
+// MarkAllRead 标记全部通知为已读
+func (s *NotificationService) MarkAllRead(p Principal) error {
+	return database.DB.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", p.UserID).
+		Update("read_at", time.Now()).Error
+}
+
+// GetSetting 获取操作者的通知推送方式，未设置时只保存站内通知
+func (s *NotificationService) GetSetting(p Principal) (*model.NotificationSetting, error) {
+	return findNotificationSetting(database.DB, p.UserID)
+}
+
+func findNotificationSetting(tx *gorm.DB, userID uint) (*model.NotificationSetting, error) {
+	var setting model.NotificationSetting
+	if err := tx.Where("user_id = ?", userID).First(&setting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.NotificationSetting{UserID: userID, Channel: model.DigestChannelNone}, nil
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// UpdateSetting 修改通知推送方式，与周报订阅互不影响
+func (s *NotificationService) UpdateSetting(p Principal, input NotificationSettingInput) (*model.NotificationSetting, error) {
+	if err := validateChannel(input.Channel, &input.WebhookURL); err != nil {
+		if errors.Is(err, errInvalidChannel) {
+			return nil, ErrInvalidNotificationSetting
+		}
+		return nil, err
+	}
+
+	var after model.NotificationSetting
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findNotificationSetting(tx, p.UserID)
+		if err != nil {
+			return err
+		}
+		secret, err := webhookSecret(input.Channel, before.WebhookSecret, input.RotateSecret)
+		if err != nil {
+			return err
+		}
+		after = model.NotificationSetting{
+			UserID:        p.UserID,
+			Channel:       input.Channel,
+			WebhookURL:    input.WebhookURL,
+			WebhookSecret: secret,
+		}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&after).Error; err != nil {
+			return err
+		}
+		// 审计日志中不保存密钥
+		beforeAudit, afterAudit := *before, after
+		beforeAudit.WebhookSecret, afterAudit.WebhookSecret = "", ""
+		return recordAudit(tx, p, model.AuditActionNotificationUpdate, model.AuditTargetUser, p.UserID, p.UserID, &beforeAudit, &afterAudit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// notify 保存站内通知，并按用户的通知推送方式（邮件或 webhook）推送。推送失败只记录日志
+func notify(userID uint, kind, title, body string) error {
+	notification := model.Notification{
+		UserID: userID,
+		Kind:   kind,
+		Title:  title,
+		Body:   body,
+	}
+	if err := database.DB.Create(&notification).Error; err != nil {
+		return err
+	}
+
+	setting, err := findNotificationSetting(database.DB, userID)
+	if err != nil {
+		return err
+	}
+	switch setting.Channel {
+	case model.DigestChannelEmail:
+		var user model.User
+		if err := database.DB.First(&user, userID).Error; err != nil {
+			return err
+		}
+		err = mailer.Send(user.Email, title, body)
+	case model.DigestChannelWebhook:
+		err = webhook.Post(setting.WebhookURL, setting.WebhookSecret, map[string]interface{}{"text": title + "\n" + body, "notification": notification})
+	}
+	if err != nil {
+		log.Printf("推送通知 %d 失败: %v", notification.ID, err)
+	}
+	return nil
+}
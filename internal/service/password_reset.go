@@ -0,0 +1,97 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/mailer"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 密码重置链接有效期
+const passwordResetTTL = time.Hour
+
+var ErrResetTokenInvalid = errors.New("重置链接无效或已过期")
+
+// ResetPassword 管理员为用户发起密码重置，向用户邮箱发送一次性重置链接，管理员不会看到任何密码
+func (s *AdminService) ResetPassword(p Principal, targetID uint) error {
+	var user model.User
+	if err := database.DB.Select("id", "username", "email").First(&user, targetID).Error; err != nil {
+		return ErrUserNotFound
+	}
+
+	token, tokenHash, err := newVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 旧的未使用令牌全部作废
+		now := time.Now()
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND consumed_at IS NULL", user.ID).
+			Update("consumed_at", &now).Error; err != nil {
+			return err
+		}
+
+		reset := model.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: tokenHash,
+			ExpiresAt: now.Add(passwordResetTTL),
+		}
+		if err := tx.Create(&reset).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionAdminResetPassword, model.AuditTargetUser, user.ID, user.ID, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", config.AppBaseURL, url.QueryEscape(token))
+	body := fmt.Sprintf("你好 %s：\n\n管理员为你的账号发起了密码重置，请在1小时内点击以下链接设置新密码：\n%s\n\n链接只能使用一次。", user.Username, link)
+	return mailer.Send(user.Email, "重置密码", body)
+}
+
+// ConfirmPasswordReset 通过邮件中的令牌设置新密码，令牌使用后立即失效
+func (s *UserService) ConfirmPasswordReset(p Principal, token, newPassword string) error {
+	sum := sha256.Sum256([]byte(token))
+	tokenHash := hex.EncodeToString(sum[:])
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var reset model.PasswordResetToken
+		if err := tx.Where("token_hash = ? AND consumed_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+			First(&reset).Error; err != nil {
+			return ErrResetTokenInvalid
+		}
+
+		var user model.User
+		if err := tx.Select("id", "version").First(&user, reset.UserID).Error; err != nil {
+			return ErrResetTokenInvalid
+		}
+
+		now := time.Now()
+		if err := tx.Model(&reset).Update("consumed_at", &now).Error; err != nil {
+			return err
+		}
+		if err := updateUserVersioned(tx, &user, map[string]interface{}{"password": string(hashedPassword)}); err != nil {
+			return err
+		}
+
+		// 重置请求未登录，操作者记为账号所属用户
+		p.UserID = user.ID
+		return recordAudit(tx, p, model.AuditActionUserPasswordReset, model.AuditTargetUser, user.ID, user.ID, nil, nil)
+	})
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 鉴权相关错误，handler 据此返回 403/404
+var (
+	ErrForbidden           = errors.New("无权限访问该资源")
+	ErrApplicationNotFound = errors.New("申请记录不存在或无权限访问")
+	ErrUserNotFound        = errors.New("用户不存在")
+)
+
+// 所有读写申请记录的查询都必须经过 ownedApplications，
+// 这样即使调用方传入了别人的ID，也只会得到"记录不存在"
+
+// ownedApplications 将查询限定在操作者本人的申请记录内
+func ownedApplications(p Principal) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("applications.user_id = ?", p.UserID)
+	}
+}
+
+// findOwnedApplication 查找操作者本人的某条申请记录
+func findOwnedApplication(tx *gorm.DB, p Principal, id uint) (*model.Application, error) {
+	var application model.Application
+	if err := tx.Scopes(ownedApplications(p)).Where("id = ?", id).First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// findOwnedDeletedApplication 查找操作者回收站中的某条申请记录
+func findOwnedDeletedApplication(tx *gorm.DB, p Principal, id uint) (*model.Application, error) {
+	var application model.Application
+	if err := tx.Unscoped().Scopes(ownedApplications(p)).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// authorizeUser 校验操作者能否访问目标用户：本人或管理员
+func authorizeUser(p Principal, targetID uint) error {
+	if p.UserID == 0 {
+		return ErrForbidden
+	}
+	if p.UserID == targetID || p.IsAdmin() {
+		return nil
+	}
+	return ErrForbidden
+}
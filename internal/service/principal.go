@@ -0,0 +1,19 @@
+package service
+
+import "internship-manager/internal/model"
+
+// Principal 当前请求的操作者
+type Principal struct {
+	UserID    uint
+	Role      string
+	IP        string
+	RequestID string
+}
+
+// SystemPrincipal 后台任务使用的系统操作者，审计日志中 actor_id 为 0
+var SystemPrincipal = Principal{Role: "system", RequestID: "system"}
+
+// IsAdmin 是否为管理员
+func (p Principal) IsAdmin() bool {
+	return p.Role == model.RoleAdmin
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/internal/search"
+	"internship-manager/pkg/database"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxQuestionLength       = 2000
+	maxApplicationQuestions = 500 // 单条申请最多返回的面试题数
+)
+
+var (
+	ErrQuestionNotFound = errors.New("面试题不存在")
+	ErrInvalidQuestion  = errors.New("题目不能为空且不超过2000个字符")
+	ErrInvalidTopic     = errors.New("无效的题目分类，可选 algorithm、system_design、behavioral、fundamentals、project、other")
+)
+
+type QuestionService struct{}
+
+// QuestionQuery 题库的筛选条件
+type QuestionQuery struct {
+	Company       string // 公司名称，模糊匹配
+	Topic         model.QuestionTopic
+	Keyword       string // 在题目、回答和复盘中模糊匹配
+	ApplicationID *uint
+	EventID       *uint
+	Page          int
+	PageSize      int
+}
+
+// QuestionItem 面试题及其来源的申请和面试日程
+type QuestionItem struct {
+	model.InterviewQuestion
+	Company          string           `json:"company"`
+	Position         string           `json:"position"`
+	EventTitle       string           `json:"event_title,omitempty"`
+	EventType        *model.EventType `json:"event_type,omitempty"`
+	EventScheduledAt *time.Time       `json:"event_scheduled_at,omitempty"`
+}
+
+// QuestionInput 新建面试题的参数
+type QuestionInput struct {
+	ApplicationID uint                  `json:"application_id" binding:"required"`
+	EventID       *uint                 `json:"event_id"`
+	Question      string                `json:"question" binding:"required"`
+	Answer        string                `json:"answer"`
+	Reflection    string                `json:"reflection"`
+	Topics        []model.QuestionTopic `json:"topics"`
+}
+
+// QuestionUpdate 修改面试题的参数，未提供的字段保持不变
+type QuestionUpdate struct {
+	EventID    *uint                 `json:"event_id"`
+	ClearEvent bool                  `json:"clear_event"` // 取消关联面试日程
+	Question   *string               `json:"question"`
+	Answer     *string               `json:"answer"`
+	Reflection *string               `json:"reflection"`
+	Topics     []model.QuestionTopic `json:"topics"` // 为 null 时不修改，空数组清空分类
+}
+
+// ListQuestions 在操作者全部申请中检索面试题。按申请筛选时按面试轮次的时间排序，否则最新的在前；
+// 回收站中的申请的题目不返回
+func (s *QuestionService) ListQuestions(p Principal, q QuestionQuery) ([]QuestionItem, int64, error) {
+	if q.Topic != "" && !q.Topic.Valid() {
+		return nil, 0, ErrInvalidTopic
+	}
+
+	db := database.DB.Table("interview_questions AS q").
+		Joins("JOIN applications a ON a.id = q.application_id AND a.deleted_at IS NULL").
+		Joins("LEFT JOIN application_events e ON e.id = q.event_id").
+		Where("q.user_id = ?", p.UserID)
+	if q.Company != "" {
+		db = db.Where("a.company LIKE ?", "%"+search.EscapeLike(q.Company)+"%")
+	}
+	if q.Topic != "" {
+		db = db.Where("FIND_IN_SET(?, q.topics) > 0", string(q.Topic))
+	}
+	if q.Keyword != "" {
+		pattern := "%" + search.EscapeLike(q.Keyword) + "%"
+		db = db.Where("(q.question LIKE ? OR q.answer LIKE ? OR q.reflection LIKE ?)", pattern, pattern, pattern)
+	}
+	if q.ApplicationID != nil {
+		db = db.Where("q.application_id = ?", *q.ApplicationID)
+	}
+	if q.EventID != nil {
+		db = db.Where("q.event_id = ?", *q.EventID)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "q.id DESC"
+	if q.ApplicationID != nil {
+		order = "e.scheduled_at IS NULL, e.scheduled_at ASC, q.id ASC"
+	}
+	var questions []QuestionItem
+	if err := db.Select("q.*, a.company, a.position, e.title AS event_title, e.type AS event_type, e.scheduled_at AS event_scheduled_at").
+		Order(order).
+		Limit(q.PageSize).
+		Offset((q.Page - 1) * q.PageSize).
+		Scan(&questions).Error; err != nil {
+		return nil, 0, err
+	}
+	return questions, total, nil
+}
+
+// ListApplicationQuestions 获取某条申请的面试题，按面试轮次的时间排序
+func (s *QuestionService) ListApplicationQuestions(p Principal, applicationID uint) ([]QuestionItem, error) {
+	if _, err := findOwnedApplication(database.DB, p, applicationID); err != nil {
+		return nil, err
+	}
+	questions, _, err := s.ListQuestions(p, QuestionQuery{ApplicationID: &applicationID, Page: 1, PageSize: maxApplicationQuestions})
+	return questions, err
+}
+
+// CreateQuestion 记录面试题，关联的申请和面试日程需属于操作者，日程需属于该申请
+func (s *QuestionService) CreateQuestion(p Principal, input QuestionInput) (*model.InterviewQuestion, error) {
+	question := model.InterviewQuestion{
+		UserID:        p.UserID,
+		ApplicationID: input.ApplicationID,
+		EventID:       input.EventID,
+		Answer:        input.Answer,
+		Reflection:    input.Reflection,
+	}
+	var err error
+	if question.Question, err = normalizeQuestion(input.Question); err != nil {
+		return nil, err
+	}
+	if question.Topics, err = normalizeTopics(input.Topics); err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := findOwnedApplication(tx, p, question.ApplicationID); err != nil {
+			return err
+		}
+		if question.EventID != nil {
+			if _, err := findOwnedEvent(tx, p, question.ApplicationID, *question.EventID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&question).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionQuestionCreate, model.AuditTargetQuestion, question.ID, p.UserID, nil, &question)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// UpdateQuestion 修改面试题
+func (s *QuestionService) UpdateQuestion(p Principal, id uint, req QuestionUpdate) (*model.InterviewQuestion, error) {
+	var after model.InterviewQuestion
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedQuestion(tx, p, id)
+		if err != nil {
+			return err
+		}
+		after = *before
+
+		if req.Question != nil {
+			if after.Question, err = normalizeQuestion(*req.Question); err != nil {
+				return err
+			}
+		}
+		if req.Answer != nil {
+			after.Answer = *req.Answer
+		}
+		if req.Reflection != nil {
+			after.Reflection = *req.Reflection
+		}
+		if req.Topics != nil {
+			if after.Topics, err = normalizeTopics(req.Topics); err != nil {
+				return err
+			}
+		}
+		if req.ClearEvent {
+			after.EventID = nil
+		} else if req.EventID != nil {
+			if _, err := findOwnedEvent(tx, p, after.ApplicationID, *req.EventID); err != nil {
+				return err
+			}
+			after.EventID = req.EventID
+		}
+
+		if err := tx.Save(&after).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionQuestionUpdate, model.AuditTargetQuestion, id, p.UserID, before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// DeleteQuestion 删除面试题
+func (s *QuestionService) DeleteQuestion(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		question, err := findOwnedQuestion(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(question).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionQuestionDelete, model.AuditTargetQuestion, id, p.UserID, question, nil)
+	})
+}
+
+// findOwnedQuestion 查找操作者本人的面试题，所属申请在回收站中时视为不存在
+func findOwnedQuestion(tx *gorm.DB, p Principal, id uint) (*model.InterviewQuestion, error) {
+	var question model.InterviewQuestion
+	if err := tx.Where("id = ? AND user_id = ?", id, p.UserID).First(&question).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	if _, err := findOwnedApplication(tx, p, question.ApplicationID); err != nil {
+		if errors.Is(err, ErrApplicationNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+func normalizeQuestion(question string) (string, error) {
+	question = strings.TrimSpace(question)
+	if question == "" || utf8.RuneCountInString(question) > maxQuestionLength {
+		return "", ErrInvalidQuestion
+	}
+	return question, nil
+}
+
+// normalizeTopics 校验分类并去重，保持原有顺序
+func normalizeTopics(topics []model.QuestionTopic) (model.TopicList, error) {
+	list := model.TopicList{}
+	seen := make(map[model.QuestionTopic]bool)
+	for _, topic := range topics {
+		if !topic.Valid() {
+			return nil, ErrInvalidTopic
+		}
+		if !seen[topic] {
+			seen[topic] = true
+			list = append(list, topic)
+		}
+	}
+	return list, nil
+}
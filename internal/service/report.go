@@ -0,0 +1,507 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/mailer"
+	"internship-manager/pkg/webhook"
+	"log"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxReportItems   = 100 // 周报每一部分最多列出的记录数
+	digestSendHour   = 8   // 每周一该时刻（用户时区）之后发送上一周的周报
+	upcomingInReport = 7 * 24 * time.Hour
+
+	webhookResolveTimeout = 5 * time.Second // 保存订阅时解析 webhook 域名的超时
+)
+
+var (
+	ErrInvalidWeek         = errors.New("无效的周，格式为 2026-W42 或 2026-10-19")
+	ErrInvalidSubscription = errors.New("无效的周报设置")
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var reportTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"date":       func(t time.Time) string { return t.Format("01-02") },
+	"datetime":   func(t time.Time) string { return t.Format("01-02 15:04") },
+	"statusName": statusName,
+	"statuses":   func() []model.ApplicationStatus { return reportStatuses },
+	"metricName": func(metric string) string { return metricNames[metric] },
+	"delta": func(n int) string {
+		if n > 0 {
+			return "+" + strconv.Itoa(n)
+		}
+		return strconv.Itoa(n)
+	},
+	"rate": func(r *float64) string {
+		if r == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", *r*100)
+	},
+	"rateDelta": func(r *float64) string {
+		if r == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%+.1f个百分点", *r*100)
+	},
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// 报告中显示的状态名称
+var statusNames = map[model.ApplicationStatus]string{
+	model.StatusSubmitted: "已投递",
+	model.StatusWritten:   "笔试中",
+	model.StatusInterview: "面试中",
+	model.StatusAccepted:  "已录用",
+	model.StatusRejected:  "已拒绝",
+	model.StatusGhosted:   "无回复",
+}
+
+// 报告中各状态的展示顺序
+var reportStatuses = []model.ApplicationStatus{
+	model.StatusSubmitted,
+	model.StatusWritten,
+	model.StatusInterview,
+	model.StatusAccepted,
+	model.StatusRejected,
+	model.StatusGhosted,
+}
+
+func statusName(status model.ApplicationStatus) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return string(status)
+}
+
+// 周报中对比的指标，顺序即展示顺序
+var reportMetrics = []string{MetricApplications, MetricResponses, MetricInterviews, MetricOffers, MetricRejections, MetricGhosted}
+
+var metricNames = map[string]string{
+	MetricApplications: "新投递",
+	MetricResponses:    "收到回复",
+	MetricInterviews:   "完成面试",
+	MetricOffers:       "录用",
+	MetricRejections:   "被拒",
+	MetricGhosted:      "无回复",
+}
+
+type ReportService struct{}
+
+// WeeklyReport 一周的求职进展
+type WeeklyReport struct {
+	Week     string    `json:"week"` // ISO 周，如 2026-W42
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"` // 不包含
+	Timezone string    `json:"tz"`
+
+	NewApplications []ReportApplication  `json:"new_applications"`
+	StatusChanges   []ReportStatusChange `json:"status_changes"`
+	UpcomingEvents  []UpcomingEvent      `json:"upcoming_events"` // 周报结束（或当前时刻）之后7天内的日程
+	Stale           []StaleApplication   `json:"stale"`           // 按停滞规则当前停滞的申请
+	Statistics      map[string]int       `json:"statistics"`      // 当前各状态的申请数
+	Metrics         []ReportMetric       `json:"metrics"`         // 本周与上一周的各项数量
+	Funnel          []ReportFunnelDelta  `json:"funnel"`          // 截至本周末与截至上周末的漏斗转化率
+}
+
+// LastDay 周报的最后一天，用于展示
+func (r *WeeklyReport) LastDay() time.Time {
+	return r.To.AddDate(0, 0, -1)
+}
+
+// ReportApplication 周报中列出的申请
+type ReportApplication struct {
+	ID        uint                    `json:"id"`
+	Company   string                  `json:"company"`
+	Position  string                  `json:"position"`
+	Status    model.ApplicationStatus `json:"status"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// ReportStatusChange 周报中的一次状态变更
+type ReportStatusChange struct {
+	ApplicationID uint                    `json:"application_id"`
+	Company       string                  `json:"company"`
+	Position      string                  `json:"position"`
+	FromStatus    model.ApplicationStatus `json:"from_status"`
+	ToStatus      model.ApplicationStatus `json:"to_status"`
+	Source        string                  `json:"source"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+// ReportMetric 指标本周与上周的数量
+type ReportMetric struct {
+	Metric   string `json:"metric"`
+	ThisWeek int    `json:"this_week"`
+	LastWeek int    `json:"last_week"`
+	Delta    int    `json:"delta"`
+}
+
+// ReportFunnelDelta 漏斗中从 From 到 To 的转化率本周与上周的对比，没有数据时为 null。
+// 依次为相邻阶段之间的转化率，最后一项为从投递到录用的整体转化率
+type ReportFunnelDelta struct {
+	From     model.ApplicationStatus `json:"from"`
+	To       model.ApplicationStatus `json:"to"`
+	ThisWeek *float64                `json:"this_week"`
+	LastWeek *float64                `json:"last_week"`
+	Delta    *float64                `json:"delta"`
+}
+
+// SubscriptionInput 修改周报订阅的参数
+type SubscriptionInput struct {
+	Channel    string `json:"channel" binding:"required"`
+	WebhookURL string `json:"webhook_url" binding:"max=512"`
+	Timezone   string `json:"timezone" binding:"max=64"`
+	// RotateSecret 重新生成 webhook 签名密钥，旧密钥立即失效
+	RotateSecret bool `json:"rotate_secret"`
+}
+
+// ParseWeek 解析周参数，返回该周周一零点。支持 ISO 周（2026-W42）或该周内任意一天（2026-10-19），为空时为本周
+func ParseWeek(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return bucketStart(time.Now(), IntervalWeek, loc), nil
+	}
+
+	if day, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return bucketStart(day, IntervalWeek, loc), nil
+	}
+
+	var year, week int
+	if _, err := fmt.Sscanf(strings.ToUpper(value), "%d-W%d", &year, &week); err != nil || week < 1 || week > 53 {
+		return time.Time{}, ErrInvalidWeek
+	}
+	// 1月4日所在的周为第1周
+	start := bucketStart(time.Date(year, 1, 4, 0, 0, 0, 0, loc), IntervalWeek, loc).AddDate(0, 0, (week-1)*7)
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return time.Time{}, ErrInvalidWeek
+	}
+	return start, nil
+}
+
+func weekKey(start time.Time) string {
+	year, week := start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// GetWeeklyReport 生成操作者从 start（周一零点）开始一周的周报
+func (s *ReportService) GetWeeklyReport(p Principal, start time.Time) (*WeeklyReport, error) {
+	loc := start.Location()
+	end := start.AddDate(0, 0, 7)
+	report := &WeeklyReport{
+		Week:     weekKey(start),
+		From:     start,
+		To:       end,
+		Timezone: loc.String(),
+	}
+
+	if err := database.DB.Model(&model.Application{}).
+		Select("id, company, position, status, created_at").
+		Scopes(ownedApplications(p)).
+		Where("deleted_at IS NULL AND created_at >= ? AND created_at < ?", start, end).
+		Order("created_at ASC, id ASC").
+		Limit(maxReportItems).
+		Scan(&report.NewApplications).Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Table("application_histories AS h").
+		Select("h.application_id, a.company, a.position, h.from_status, h.to_status, h.source, h.created_at").
+		Joins("JOIN applications a ON a.id = h.application_id AND a.deleted_at IS NULL").
+		Where("h.user_id = ? AND h.action = ? AND h.created_at >= ? AND h.created_at < ?", p.UserID, model.HistoryStatus, start, end).
+		Order("h.created_at ASC, h.id ASC").
+		Limit(maxReportItems).
+		Scan(&report.StatusChanges).Error; err != nil {
+		return nil, err
+	}
+
+	// 本周尚未结束时从当前时刻算起
+	upcomingFrom := end
+	if now := time.Now(); now.Before(end) {
+		upcomingFrom = now
+	}
+	events, err := pendingEventsBetween(p, upcomingFrom, upcomingFrom.Add(upcomingInReport))
+	if err != nil {
+		return nil, err
+	}
+	report.UpcomingEvents = events
+
+	// 时间按周报时区展示
+	for i := range report.NewApplications {
+		report.NewApplications[i].CreatedAt = report.NewApplications[i].CreatedAt.In(loc)
+	}
+	for i := range report.StatusChanges {
+		report.StatusChanges[i].CreatedAt = report.StatusChanges[i].CreatedAt.In(loc)
+	}
+	for i := range report.UpcomingEvents {
+		report.UpcomingEvents[i].ScheduledAt = report.UpcomingEvents[i].ScheduledAt.In(loc)
+	}
+
+	stale, err := (&StaleService{}).ListStale(p)
+	if err != nil {
+		return nil, err
+	}
+	report.Stale = stale.Applications
+	if len(report.Stale) > maxReportItems {
+		report.Stale = report.Stale[:maxReportItems]
+	}
+
+	report.Statistics, err = (&ApplicationService{}).GetApplicationStatistics(p, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := (&AnalyticsService{}).GetTimeseries(p, TimeseriesQuery{
+		Metrics:  reportMetrics,
+		Interval: IntervalWeek,
+		Location: loc,
+		From:     timePtr(start.AddDate(0, 0, -7)),
+		To:       &end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range reportMetrics {
+		points := series.Series[metric]
+		m := ReportMetric{Metric: metric}
+		if len(points) == 2 {
+			m.LastWeek, m.ThisWeek = points[0].Count, points[1].Count
+		}
+		m.Delta = m.ThisWeek - m.LastWeek
+		report.Metrics = append(report.Metrics, m)
+	}
+
+	// 漏斗按周末时刻的状态计算，本周尚未结束时按当前状态
+	thisFunnel, err := (&AnalyticsService{}).GetFunnel(p, AnalyticsFilter{AsOf: &upcomingFrom})
+	if err != nil {
+		return nil, err
+	}
+	lastFunnel, err := (&AnalyticsService{}).GetFunnel(p, AnalyticsFilter{AsOf: &start})
+	if err != nil {
+		return nil, err
+	}
+	report.Funnel = funnelDeltas(thisFunnel, lastFunnel)
+	return report, nil
+}
+
+// funnelDeltas 对比两个漏斗的相邻阶段转化率和整体转化率
+func funnelDeltas(this, last *Funnel) []ReportFunnelDelta {
+	deltas := make([]ReportFunnelDelta, 0, len(funnelStages))
+	for i := 0; i+1 < len(funnelStages); i++ {
+		deltas = append(deltas, newFunnelDelta(funnelStages[i], funnelStages[i+1],
+			this.Stages[i].ConversionRate, last.Stages[i].ConversionRate))
+	}
+	return append(deltas, newFunnelDelta(funnelStages[0], funnelStages[len(funnelStages)-1],
+		this.OverallConversion, last.OverallConversion))
+}
+
+func newFunnelDelta(from, to model.ApplicationStatus, this, last *float64) ReportFunnelDelta {
+	d := ReportFunnelDelta{From: from, To: to, ThisWeek: this, LastWeek: last}
+	if this != nil && last != nil {
+		delta := *this - *last
+		d.Delta = &delta
+	}
+	return d
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// RenderWeeklyReport 将周报渲染为纯文本，用于邮件和 webhook
+func RenderWeeklyReport(username string, report *WeeklyReport) (string, error) {
+	var buf bytes.Buffer
+	err := reportTemplates.ExecuteTemplate(&buf, "weekly_digest.txt.tmpl", map[string]interface{}{
+		"Username": username,
+		"Report":   report,
+		"Link":     config.AppBaseURL + "/reports/weekly?week=" + report.Week,
+	})
+	return buf.String(), err
+}
+
+// GetSubscription 获取操作者的周报订阅设置，未设置时不发送
+func (s *ReportService) GetSubscription(p Principal) (*model.DigestSubscription, error) {
+	return findSubscription(database.DB, p.UserID)
+}
+
+func findSubscription(tx *gorm.DB, userID uint) (*model.DigestSubscription, error) {
+	var sub model.DigestSubscription
+	if err := tx.Where("user_id = ?", userID).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.DigestSubscription{UserID: userID, Channel: model.DigestChannelNone}, nil
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// subscriptionLocation 订阅设置的时区，为空或无效时使用服务器时区
+func subscriptionLocation(tz string) *time.Location {
+	if tz == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// UpdateSubscription 修改周报订阅设置
+func (s *ReportService) UpdateSubscription(p Principal, input SubscriptionInput) (*model.DigestSubscription, error) {
+	if err := validateSubscription(&input); err != nil {
+		return nil, err
+	}
+
+	var after model.DigestSubscription
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findSubscription(tx, p.UserID)
+		if err != nil {
+			return err
+		}
+		secret, err := webhookSecret(input.Channel, before.WebhookSecret, input.RotateSecret)
+		if err != nil {
+			return err
+		}
+		after = model.DigestSubscription{
+			UserID:        p.UserID,
+			Channel:       input.Channel,
+			WebhookURL:    input.WebhookURL,
+			WebhookSecret: secret,
+			Timezone:      input.Timezone,
+			LastSentWeek:  before.LastSentWeek,
+		}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&after).Error; err != nil {
+			return err
+		}
+		// 审计日志中不保存密钥
+		beforeAudit, afterAudit := *before, after
+		beforeAudit.WebhookSecret, afterAudit.WebhookSecret = "", ""
+		return recordAudit(tx, p, model.AuditActionDigestUpdate, model.AuditTargetUser, p.UserID, p.UserID, &beforeAudit, &afterAudit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+func validateSubscription(input *SubscriptionInput) error {
+	if err := validateChannel(input.Channel, &input.WebhookURL); err != nil {
+		if errors.Is(err, errInvalidChannel) {
+			return ErrInvalidSubscription
+		}
+		return err
+	}
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return ErrInvalidSubscription
+		}
+	}
+	return nil
+}
+
+var errInvalidChannel = errors.New("无效的推送方式")
+
+// validateChannel 校验推送方式（周报和通知共用），不使用 webhook 时清空地址
+func validateChannel(channel string, webhookURL *string) error {
+	switch channel {
+	case model.DigestChannelNone, model.DigestChannelEmail:
+		*webhookURL = ""
+	case model.DigestChannelWebhook:
+		ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+		defer cancel()
+		return webhook.ValidateURL(ctx, *webhookURL)
+	default:
+		return errInvalidChannel
+	}
+	return nil
+}
+
+// webhookSecret 使用 webhook 时才需要签名密钥，首次选择或要求轮换时生成新密钥，否则沿用 current
+func webhookSecret(channel, current string, rotate bool) (string, error) {
+	if channel != model.DigestChannelWebhook {
+		return "", nil
+	}
+	if current != "" && !rotate {
+		return current, nil
+	}
+	secret, _, err := newVerificationToken()
+	return secret, err
+}
+
+// SendWeeklyDigests 向订阅了周报的用户发送上一周的周报，由后台任务调用。
+// 每周一 digestSendHour 点（用户时区）之后发送，已发送过的周不重复发送
+func (s *ReportService) SendWeeklyDigests() error {
+	var subs []model.DigestSubscription
+	if err := database.DB.Where("channel <> ?", model.DigestChannelNone).Find(&subs).Error; err != nil {
+		return err
+	}
+
+	for i := range subs {
+		sub := &subs[i]
+		loc := subscriptionLocation(sub.Timezone)
+		now := time.Now().In(loc)
+		thisWeek := bucketStart(now, IntervalWeek, loc)
+		if now.Sub(thisWeek) < digestSendHour*time.Hour {
+			continue
+		}
+		lastWeek := thisWeek.AddDate(0, 0, -7)
+		if sub.LastSentWeek == weekKey(lastWeek) {
+			continue
+		}
+
+		// 单个用户发送失败不影响其他用户，下次任务执行时重试
+		if err := s.sendDigest(sub, lastWeek); err != nil {
+			log.Printf("发送用户 %d 的周报失败: %v", sub.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (s *ReportService) sendDigest(sub *model.DigestSubscription, week time.Time) error {
+	var user model.User
+	if err := database.DB.First(&user, sub.UserID).Error; err != nil {
+		return err
+	}
+	if user.Disabled || user.DeletionRequestedAt != nil {
+		return nil
+	}
+
+	p := Principal{UserID: user.ID, Role: user.Role, RequestID: SystemPrincipal.RequestID}
+	report, err := s.GetWeeklyReport(p, week)
+	if err != nil {
+		return err
+	}
+	text, err := RenderWeeklyReport(user.Username, report)
+	if err != nil {
+		return err
+	}
+
+	switch sub.Channel {
+	case model.DigestChannelEmail:
+		err = mailer.Send(user.Email, "求职周报 "+report.Week, text)
+	case model.DigestChannelWebhook:
+		err = webhook.Post(sub.WebhookURL, sub.WebhookSecret, map[string]interface{}{"text": text, "report": report})
+	}
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Model(&model.DigestSubscription{}).
+		Where("user_id = ?", sub.UserID).
+		UpdateColumn("last_sent_week", report.Week).Error
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"internship-manager/pkg/pdf"
+	"strconv"
+	"time"
+)
+
+const (
+	maxSummaryApplications = 200                 // 总结中最多列出的进行中申请数
+	summaryEventsWithin    = 14 * 24 * time.Hour // 总结中列出的日程范围
+	summaryTrendWeeks      = 12                  // 趋势图的周数
+
+	pdfMargin     = 40.0
+	pdfBodySize   = 9.0
+	pdfRowHeight  = 16.0
+	pdfFooterSize = 8.0
+)
+
+// 图表配色
+var (
+	pdfPrimary   = pdf.Color{R: 0.22, G: 0.45, B: 0.78}
+	pdfSecondary = pdf.Color{R: 0.93, G: 0.55, B: 0.2}
+)
+
+var eventTypeNames = map[model.EventType]string{
+	model.EventWritten:   "笔试",
+	model.EventInterview: "面试",
+	model.EventOther:     "其他",
+	model.EventMock:      "模拟面试",
+}
+
+// pdfColumn 表格的一列
+type pdfColumn struct {
+	title string
+	width float64
+}
+
+// summaryLayout 从上到下排版，空间不足时自动换页
+type summaryLayout struct {
+	doc   *pdf.Document
+	pages []*pdf.Page
+	page  *pdf.Page
+	y     float64
+}
+
+func newSummaryLayout(title string) *summaryLayout {
+	l := &summaryLayout{doc: pdf.New()}
+	l.doc.Title = title
+	l.doc.SetFont(config.PDFFont)
+	l.newPage()
+	return l
+}
+
+func (l *summaryLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.pages = append(l.pages, l.page)
+	l.y = pdfMargin
+}
+
+func (l *summaryLayout) contentWidth() float64 {
+	return l.doc.Width - 2*pdfMargin
+}
+
+// ensure 剩余空间不足 h 时换页
+func (l *summaryLayout) ensure(h float64) {
+	if l.y+h > l.doc.Height-pdfMargin {
+		l.newPage()
+	}
+}
+
+func (l *summaryLayout) heading(text string) {
+	l.ensure(60)
+	l.y += 22
+	l.page.Text(pdfMargin, l.y, 13, pdf.Black, text)
+	l.y += 6
+	l.page.Line(pdfMargin, l.y, l.doc.Width-pdfMargin, l.y, 0.8, pdfPrimary)
+	l.y += 14
+}
+
+func (l *summaryLayout) paragraph(text string) {
+	l.ensure(pdfRowHeight)
+	l.page.Text(pdfMargin, l.y+pdfBodySize, pdfBodySize, pdf.Black, text)
+	l.y += pdfRowHeight
+}
+
+// table 绘制表格，跨页时在新页重复表头
+func (l *summaryLayout) table(columns []pdfColumn, rows [][]string) {
+	header := func() {
+		l.page.FillRect(pdfMargin, l.y, l.contentWidth(), pdfRowHeight, pdf.LightGray)
+		l.row(columns, nil)
+	}
+
+	l.ensure(2 * pdfRowHeight)
+	header()
+	for _, cells := range rows {
+		if l.y+pdfRowHeight > l.doc.Height-pdfMargin {
+			l.newPage()
+			header()
+		}
+		l.row(columns, cells)
+		l.page.Line(pdfMargin, l.y, l.doc.Width-pdfMargin, l.y, 0.3, pdf.LightGray)
+	}
+}
+
+// row 绘制一行，cells 为 nil 时绘制表头
+func (l *summaryLayout) row(columns []pdfColumn, cells []string) {
+	x := pdfMargin
+	for i, col := range columns {
+		text := col.title
+		if cells != nil {
+			text = cells[i]
+		}
+		l.page.Text(x+4, l.y+pdfBodySize+3, pdfBodySize, pdf.Black, pdf.Truncate(text, pdfBodySize, col.width-8))
+		x += col.width
+	}
+	l.y += pdfRowHeight
+}
+
+// footer 在每页底部写页码
+func (l *summaryLayout) footer(generatedAt string) {
+	for i, page := range l.pages {
+		text := fmt.Sprintf("第 %d / %d 页    生成于 %s", i+1, len(l.pages), generatedAt)
+		page.Text(pdfMargin, l.doc.Height-pdfMargin/2, pdfFooterSize, pdf.Gray, text)
+	}
+}
+
+// SummaryPDF 生成操作者的求职总结 PDF：各状态数量、投递漏斗、近12周趋势图、进行中的申请和接下来14天的日程
+func (s *ReportService) SummaryPDF(p Principal, loc *time.Location) ([]byte, error) {
+	user, err := NewUserService().GetUserByID(p, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(loc)
+
+	stats, err := (&ApplicationService{}).GetApplicationStatistics(p, nil)
+	if err != nil {
+		return nil, err
+	}
+	funnel, err := (&AnalyticsService{}).GetFunnel(p, AnalyticsFilter{})
+	if err != nil {
+		return nil, err
+	}
+	from := bucketStart(now, IntervalWeek, loc).AddDate(0, 0, -7*(summaryTrendWeeks-1))
+	trend, err := (&AnalyticsService{}).GetTimeseries(p, TimeseriesQuery{
+		Metrics:  []string{MetricApplications, MetricInterviews},
+		Interval: IntervalWeek,
+		Location: loc,
+		From:     &from,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var active []model.Application
+	if err := database.DB.Select(listColumns).
+		Scopes(ownedApplications(p)).
+		Where("deleted_at IS NULL AND archived_at IS NULL AND status IN ?",
+			[]model.ApplicationStatus{model.StatusSubmitted, model.StatusWritten, model.StatusInterview}).
+		Order("status_rank DESC, updated_at DESC").
+		Limit(maxSummaryApplications).
+		Find(&active).Error; err != nil {
+		return nil, err
+	}
+	events, err := pendingEventsBetween(p, now, now.Add(summaryEventsWithin))
+	if err != nil {
+		return nil, err
+	}
+
+	l := newSummaryLayout("求职总结 - " + user.Username)
+	l.page.Text(pdfMargin, l.y+20, 20, pdf.Black, "求职总结")
+	l.y += 34
+	l.paragraph(fmt.Sprintf("%s    %s（%s）", user.Username, now.Format("2006-01-02"), loc.String()))
+
+	l.heading("概况")
+	total := 0
+	for _, status := range reportStatuses {
+		total += stats[string(status)]
+	}
+	l.paragraph(fmt.Sprintf("共 %d 条申请", total))
+	for _, status := range reportStatuses {
+		l.paragraph(fmt.Sprintf("%s：%d", statusName(status), stats[string(status)]))
+	}
+
+	l.heading("投递漏斗")
+	drawFunnel(l, funnel)
+
+	l.heading(fmt.Sprintf("近%d周趋势", summaryTrendWeeks))
+	drawTrend(l, trend)
+
+	l.heading(fmt.Sprintf("进行中的申请（%d）", len(active)))
+	rows := make([][]string, len(active))
+	for i, app := range active {
+		next := ""
+		if app.NextEventAt != nil {
+			next = app.NextEventAt.In(loc).Format("01-02 15:04")
+		}
+		rows[i] = []string{app.Company, app.Position, statusName(app.Status), next, app.UpdatedAt.In(loc).Format("2006-01-02")}
+	}
+	l.table([]pdfColumn{{"公司", 150}, {"职位", 150}, {"状态", 55}, {"最近日程", 80}, {"更新时间", 80}}, rows)
+
+	l.heading(fmt.Sprintf("接下来%d天的日程", int(summaryEventsWithin.Hours()/24)))
+	if len(events) == 0 {
+		l.paragraph("无")
+	} else {
+		rows = make([][]string, len(events))
+		for i, e := range events {
+			rows[i] = []string{e.ScheduledAt.In(loc).Format("01-02 15:04"), e.Company, e.Position, eventTypeNames[e.Type], e.Title}
+		}
+		l.table([]pdfColumn{{"时间", 75}, {"公司", 130}, {"职位", 120}, {"类型", 45}, {"说明", 145}}, rows)
+	}
+
+	l.footer(now.Format("2006-01-02 15:04"))
+
+	var buf bytes.Buffer
+	if _, err := l.doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawFunnel 横向条形图，条的长度为到达数占投递数的比例
+func drawFunnel(l *summaryLayout, funnel *Funnel) {
+	const labelWidth, barHeight = 60.0, 14.0
+	barMax := l.contentWidth() - labelWidth - 140
+
+	base := 0
+	if len(funnel.Stages) > 0 {
+		base = funnel.Stages[0].Reached
+	}
+	for _, stage := range funnel.Stages {
+		l.ensure(barHeight + 8)
+		l.page.Text(pdfMargin, l.y+barHeight-3, pdfBodySize, pdf.Black, statusName(stage.Status))
+
+		width := 0.0
+		if base > 0 {
+			width = barMax * float64(stage.Reached) / float64(base)
+		}
+		l.page.FillRect(pdfMargin+labelWidth, l.y, barMax, barHeight, pdf.LightGray)
+		if width > 0 {
+			l.page.FillRect(pdfMargin+labelWidth, l.y, width, barHeight, pdfPrimary)
+		}
+
+		label := strconv.Itoa(stage.Reached)
+		if stage.ConversionRate != nil {
+			label += fmt.Sprintf("    转化 %.0f%%", *stage.ConversionRate*100)
+		}
+		l.page.Text(pdfMargin+labelWidth+barMax+8, l.y+barHeight-3, pdfBodySize, pdf.Black, label)
+		l.y += barHeight + 8
+	}
+	if funnel.OverallConversion != nil {
+		l.paragraph(fmt.Sprintf("投递到录用：%.1f%%", *funnel.OverallConversion*100))
+	}
+}
+
+// drawTrend 每周的新投递和完成面试数量柱状图
+func drawTrend(l *summaryLayout, trend *Timeseries) {
+	const chartHeight, axisLabel = 140.0, 30.0
+	l.ensure(chartHeight + 50)
+
+	applications := trend.Series[MetricApplications]
+	interviews := trend.Series[MetricInterviews]
+	maxCount := 1
+	for i := range applications {
+		if applications[i].Count > maxCount {
+			maxCount = applications[i].Count
+		}
+		if i < len(interviews) && interviews[i].Count > maxCount {
+			maxCount = interviews[i].Count
+		}
+	}
+
+	left := pdfMargin + axisLabel
+	width := l.contentWidth() - axisLabel
+	top, bottom := l.y, l.y+chartHeight
+
+	// 坐标轴和最大值刻度
+	l.page.Line(left, top, left, bottom, 0.5, pdf.Gray)
+	l.page.Line(left, bottom, left+width, bottom, 0.5, pdf.Gray)
+	l.page.Line(left, top, left+width, top, 0.3, pdf.LightGray)
+	l.page.Text(pdfMargin, top+pdfFooterSize, pdfFooterSize, pdf.Gray, strconv.Itoa(maxCount))
+	l.page.Text(pdfMargin, bottom, pdfFooterSize, pdf.Gray, "0")
+
+	if n := len(applications); n > 0 {
+		slot := width / float64(n)
+		barWidth := slot * 0.35
+		for i, point := range applications {
+			x := left + slot*float64(i) + slot*0.15
+			h := chartHeight * float64(point.Count) / float64(maxCount)
+			l.page.FillRect(x, bottom-h, barWidth, h, pdfPrimary)
+			if i < len(interviews) {
+				h = chartHeight * float64(interviews[i].Count) / float64(maxCount)
+				l.page.FillRect(x+barWidth, bottom-h, barWidth, h, pdfSecondary)
+			}
+			if i%2 == 0 {
+				l.page.Text(x, bottom+12, pdfFooterSize, pdf.Gray, point.Start.In(trend.From.Location()).Format("01-02"))
+			}
+		}
+	}
+	l.y = bottom + 22
+
+	// 图例
+	l.page.FillRect(left, l.y, 10, 8, pdfPrimary)
+	l.page.Text(left+14, l.y+8, pdfFooterSize, pdf.Black, metricNames[MetricApplications])
+	l.page.FillRect(left+80, l.y, 10, 8, pdfSecondary)
+	l.page.Text(left+94, l.y+8, pdfFooterSize, pdf.Black, metricNames[MetricInterviews])
+	l.y += 16
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/internal/search"
+)
+
+// 搜索时最多取回的命中条数，分页在命中结果内进行，超出的部分在响应中以 truncated 标明
+const maxSearchHits = 1000
+
+var searchBackend search.Backend
+
+// InitSearch 设置全文检索后端，由 main 在启动时调用
+func InitSearch(backend search.Backend) {
+	searchBackend = backend
+}
+
+// searchApplicationIDs 按相关度返回命中的申请ID，命中超过 maxSearchHits 条时只返回相关度最高的部分，truncated 为 true
+func searchApplicationIDs(p Principal, query search.Query) ([]uint, bool, error) {
+	// 多取一条用于判断是否超出上限
+	hits, err := searchBackend.Search(p.UserID, query, maxSearchHits+1)
+	if err != nil {
+		return nil, false, err
+	}
+	truncated := len(hits) > maxSearchHits
+	if truncated {
+		hits = hits[:maxSearchHits]
+	}
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids, truncated, nil
+}
+
+// attachHighlights 为搜索结果生成各字段的高亮摘要，只包含命中的字段
+func attachHighlights(applications []model.Application, query search.Query) {
+	for i := range applications {
+		app := &applications[i]
+		fields := map[string]string{
+			search.FieldCompany:  app.Company,
+			search.FieldPosition: app.Position,
+			search.FieldNotes:    app.Notes,
+		}
+		for field, text := range fields {
+			var terms []string
+			for _, term := range query.Terms {
+				if term.Matches(field) {
+					terms = append(terms, term.Text)
+				}
+			}
+			if snippet := search.Highlight(text, terms); snippet != "" {
+				if app.Highlights == nil {
+					app.Highlights = make(map[string]string)
+				}
+				app.Highlights[field] = snippet
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultStaleDays = 30  // 默认规则：超过30天没有修改视为停滞
+	maxStaleDays     = 365 // 天数上限
+	maxStaleList     = 500 // 停滞列表最多返回的记录数
+	staleBatchSize   = 200 // 后台任务每个用户每次最多处理的记录数
+)
+
+var ErrInvalidStaleRule = errors.New("无效的停滞规则")
+
+// staleRuleStatuses 可参与停滞判断的状态，已有结果的状态不需要提醒
+var staleRuleStatuses = map[model.ApplicationStatus]bool{
+	model.StatusSubmitted: true,
+	model.StatusWritten:   true,
+	model.StatusInterview: true,
+}
+
+type StaleService struct{}
+
+// StaleRuleInput 修改停滞规则的参数
+type StaleRuleInput struct {
+	StaleDays  int                       `json:"stale_days" binding:"required"`
+	Statuses   []model.ApplicationStatus `json:"statuses"` // 为空时只判断已投递
+	Action     string                    `json:"action"`   // none、rejected 或 ghosted，为空等同 none
+	ActionDays int                       `json:"action_days"`
+}
+
+// StaleApplication 停滞的申请
+type StaleApplication struct {
+	model.Application
+	IdleDays     int        `json:"idle_days"`                // 距最后一次修改的天数
+	AutoActionAt *time.Time `json:"auto_action_at,omitempty"` // 将被系统自动处理的时间，规则不自动处理时为空
+}
+
+// StaleList 停滞申请列表及使用的规则
+type StaleList struct {
+	Rule         model.StaleRule    `json:"rule"`
+	Applications []StaleApplication `json:"applications"`
+}
+
+// defaultStaleRule 用户未设置规则时使用的默认规则：已投递超过30天未变化时提示，不自动处理
+func defaultStaleRule(userID uint) model.StaleRule {
+	return model.StaleRule{
+		UserID:    userID,
+		StaleDays: defaultStaleDays,
+		Statuses:  model.StatusList{model.StatusSubmitted},
+		Action:    model.StaleActionNone,
+	}
+}
+
+// GetRule 获取操作者的停滞规则，未设置时返回默认规则
+func (s *StaleService) GetRule(p Principal) (*model.StaleRule, error) {
+	return findStaleRule(database.DB, p.UserID)
+}
+
+func findStaleRule(tx *gorm.DB, userID uint) (*model.StaleRule, error) {
+	var rule model.StaleRule
+	if err := tx.Where("user_id = ?", userID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			rule = defaultStaleRule(userID)
+			return &rule, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule 修改操作者的停滞规则
+func (s *StaleService) UpdateRule(p Principal, input StaleRuleInput) (*model.StaleRule, error) {
+	rule, err := staleRuleFromInput(p.UserID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findStaleRule(tx, p.UserID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(rule).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionStaleRuleUpdate, model.AuditTargetUser, p.UserID, p.UserID, before, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// staleRuleFromInput 校验参数并补全默认值
+func staleRuleFromInput(userID uint, input StaleRuleInput) (*model.StaleRule, error) {
+	if input.StaleDays < 1 || input.StaleDays > maxStaleDays {
+		return nil, ErrInvalidStaleRule
+	}
+
+	rule := &model.StaleRule{
+		UserID:     userID,
+		StaleDays:  input.StaleDays,
+		Action:     input.Action,
+		ActionDays: input.ActionDays,
+	}
+
+	seen := make(map[model.ApplicationStatus]bool)
+	for _, status := range input.Statuses {
+		if !staleRuleStatuses[status] {
+			return nil, ErrInvalidStaleRule
+		}
+		if !seen[status] {
+			seen[status] = true
+			rule.Statuses = append(rule.Statuses, status)
+		}
+	}
+	if len(rule.Statuses) == 0 {
+		rule.Statuses = model.StatusList{model.StatusSubmitted}
+	}
+
+	switch rule.Action {
+	case "", model.StaleActionNone:
+		rule.Action = model.StaleActionNone
+		rule.ActionDays = 0
+	case model.StaleActionRejected, model.StaleActionGhosted:
+		// 未指定时到达停滞天数即自动处理
+		if rule.ActionDays == 0 {
+			rule.ActionDays = rule.StaleDays
+		}
+		if rule.ActionDays < rule.StaleDays || rule.ActionDays > maxStaleDays {
+			return nil, ErrInvalidStaleRule
+		}
+	default:
+		return nil, ErrInvalidStaleRule
+	}
+	return rule, nil
+}
+
+// staleApplications 规则下超过 days 天没有修改的申请：未删除、未归档，且没有尚未到来的日程
+func staleApplications(db *gorm.DB, rule *model.StaleRule, days int, now time.Time) *gorm.DB {
+	return db.Model(&model.Application{}).
+		Where("applications.user_id = ?", rule.UserID).
+		Where("applications.deleted_at IS NULL AND applications.archived_at IS NULL").
+		Where("applications.status IN ?", []model.ApplicationStatus(rule.Statuses)).
+		Where("applications.updated_at < ?", now.AddDate(0, 0, -days)).
+		Where("(applications.next_event_at IS NULL OR applications.next_event_at < ?)", now)
+}
+
+// ListStale 按操作者的规则列出停滞的申请，最久没有修改的在前
+func (s *StaleService) ListStale(p Principal) (*StaleList, error) {
+	rule, err := findStaleRule(database.DB, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var applications []model.Application
+	if err := staleApplications(database.DB, rule, rule.StaleDays, now).
+		Order("applications.updated_at ASC, applications.id ASC").
+		Limit(maxStaleList).
+		Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	if err := attachTags(applications); err != nil {
+		return nil, err
+	}
+
+	list := &StaleList{Rule: *rule, Applications: make([]StaleApplication, len(applications))}
+	for i, app := range applications {
+		item := StaleApplication{
+			Application: app,
+			IdleDays:    int(now.Sub(app.UpdatedAt).Hours() / 24),
+		}
+		if rule.Action != model.StaleActionNone {
+			at := app.UpdatedAt.AddDate(0, 0, rule.ActionDays)
+			item.AutoActionAt = &at
+		}
+		list.Applications[i] = item
+	}
+	return list, nil
+}
+
+// ApplyStaleRules 按用户设置的规则自动处理长期停滞的申请，由后台任务调用。
+// 每次状态变更都以系统身份记录历史和审计日志。与任务提醒一样跳过被禁用和申请注销的用户
+func (s *StaleService) ApplyStaleRules() error {
+	var rules []model.StaleRule
+	if err := database.DB.
+		Joins("JOIN users ON users.id = stale_rules.user_id AND users.disabled = ? AND users.deletion_requested_at IS NULL", false).
+		Where("stale_rules.action <> ?", model.StaleActionNone).
+		Find(&rules).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range rules {
+		// 单个用户的规则处理失败不影响其他用户
+		if err := applyStaleRule(&rules[i], now); err != nil {
+			log.Printf("处理用户 %d 的停滞规则失败: %v", rules[i].UserID, err)
+		}
+	}
+	return nil
+}
+
+func applyStaleRule(rule *model.StaleRule, now time.Time) error {
+	status := model.ApplicationStatus(rule.Action)
+	detail := fmt.Sprintf("超过%d天无进展，系统自动处理", rule.ActionDays)
+
+	var ids []uint
+	if err := staleApplications(database.DB, rule, rule.ActionDays, now).
+		Limit(staleBatchSize).
+		Pluck("applications.id", &ids).Error; err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			// 在事务中重新判断，期间被用户修改过、或用户被禁用和申请注销的记录跳过
+			var application model.Application
+			result := staleApplications(tx, rule, rule.ActionDays, now).
+				Joins("JOIN users ON users.id = applications.user_id AND users.disabled = ? AND users.deletion_requested_at IS NULL", false).
+				Where("applications.id = ?", id).
+				Limit(1).
+				Find(&application)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return setStatusWithDetailTx(tx, SystemPrincipal, model.HistorySourceSystem, &application, status, detail)
+		})
+		if err != nil {
+			// 版本冲突说明用户刚修改过，下次再判断
+			var conflict *VersionConflictError
+			if !errors.As(err, &conflict) {
+				log.Printf("自动处理停滞申请 %d 失败: %v", id, err)
+			}
+		}
+	}
+	return nil
+}
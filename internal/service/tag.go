@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidTag = errors.New("标签名称不能为空且不超过32个字符")
+
+type TagService struct{}
+
+// TagWithCount 标签及其关联的申请数量
+type TagWithCount struct {
+	model.Tag
+	ApplicationCount int `json:"application_count"`
+}
+
+// ListTags 获取操作者的全部标签
+func (s *TagService) ListTags(p Principal) ([]TagWithCount, error) {
+	var tags []TagWithCount
+	err := database.DB.Model(&model.Tag{}).
+		Select("tags.*, COUNT(applications.id) AS application_count").
+		Joins("LEFT JOIN application_tags ON application_tags.tag_id = tags.id").
+		Joins("LEFT JOIN applications ON applications.id = application_tags.application_id AND applications.deleted_at IS NULL").
+		Where("tags.user_id = ?", p.UserID).
+		Group("tags.id").
+		Order("tags.name ASC").
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// attachTags 批量加载申请的标签
+func attachTags(applications []model.Application) error {
+	if len(applications) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(applications))
+	for i, app := range applications {
+		ids[i] = app.ID
+	}
+
+	var rows []struct {
+		ApplicationID uint
+		model.Tag
+	}
+	if err := database.DB.Table("application_tags").
+		Select("application_tags.application_id, tags.*").
+		Joins("JOIN tags ON tags.id = application_tags.tag_id").
+		Where("application_tags.application_id IN ?", ids).
+		Order("tags.name ASC").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	tagsByApp := make(map[uint][]model.Tag)
+	for _, row := range rows {
+		tagsByApp[row.ApplicationID] = append(tagsByApp[row.ApplicationID], row.Tag)
+	}
+	for i := range applications {
+		applications[i].Tags = tagsByApp[applications[i].ID]
+	}
+	return nil
+}
+
+// normalizeTagName 规范化标签名称
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 32 {
+		return "", ErrInvalidTag
+	}
+	return name, nil
+}
+
+// findOrCreateTag 获取操作者的同名标签，不存在时创建
+func findOrCreateTag(tx *gorm.DB, p Principal, name string) (*model.Tag, error) {
+	var tag model.Tag
+	err := tx.Where("user_id = ? AND name = ?", p.UserID, name).First(&tag).Error
+	if err == nil {
+		return &tag, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	tag = model.Tag{UserID: p.UserID, Name: name}
+	if err := tx.Create(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// addTagTx 给申请添加标签，调用方需已完成归属校验
+func addTagTx(tx *gorm.DB, p Principal, source string, application *model.Application, name string) error {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return err
+	}
+	tag, err := findOrCreateTag(tx, p, name)
+	if err != nil {
+		return err
+	}
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ApplicationTag{ApplicationID: application.ID, TagID: tag.ID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	if err := recordHistory(tx, p, source, model.ApplicationHistory{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		Action:        model.HistoryTagAdd,
+		Detail:        name,
+	}); err != nil {
+		return err
+	}
+	return recordAudit(tx, p, model.AuditActionApplicationTagAdd, model.AuditTargetApplication, application.ID, application.UserID,
+		nil, map[string]interface{}{"tag": name})
+}
+
+// removeTagTx 移除申请上的标签，调用方需已完成归属校验
+func removeTagTx(tx *gorm.DB, p Principal, source string, application *model.Application, name string) error {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return err
+	}
+
+	var tag model.Tag
+	if err := tx.Where("user_id = ? AND name = ?", p.UserID, name).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	result := tx.Where("application_id = ? AND tag_id = ?", application.ID, tag.ID).Delete(&model.ApplicationTag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	if err := recordHistory(tx, p, source, model.ApplicationHistory{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		Action:        model.HistoryTagRemove,
+		Detail:        name,
+	}); err != nil {
+		return err
+	}
+	return recordAudit(tx, p, model.AuditActionApplicationTagRemove, model.AuditTargetApplication, application.ID, application.UserID,
+		map[string]interface{}{"tag": name}, nil)
+}
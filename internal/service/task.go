@@ -0,0 +1,298 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 任务列表的截止时间筛选
+const (
+	TaskDueToday   = "today"   // 今天到期
+	TaskDueOverdue = "overdue" // 已过期未完成
+	TaskDueWeek    = "week"    // 今天起7天内到期
+)
+
+const (
+	maxTaskList       = 500
+	taskReminderLead  = time.Hour // 截止前多久发送提醒
+	taskReminderBatch = 500
+)
+
+var (
+	ErrTaskNotFound = errors.New("任务不存在")
+	ErrInvalidTask  = errors.New("任务标题不能为空且不超过128个字符")
+	ErrInvalidDue   = errors.New("无效的截止时间筛选，可选 today、overdue、week")
+)
+
+// taskTemplate 自动创建的跟进任务
+type taskTemplate struct {
+	kind  string        // 与触发来源一起组成去重键
+	title string        // 标题，%s 为公司名
+	after time.Duration // 截止时间相对触发时刻的偏移
+}
+
+// 面试日程标记完成后创建的任务
+var interviewDoneTasks = []taskTemplate{
+	{kind: "thank_you", title: "给%s的面试官发送感谢信", after: 24 * time.Hour},
+	{kind: "hr_follow_up", title: "向%s的HR询问面试结果", after: 14 * 24 * time.Hour},
+}
+
+// 状态变为已录用后创建的任务
+var acceptedTasks = []taskTemplate{
+	{kind: "offer_reply", title: "确认%s的offer并回复HR", after: 3 * 24 * time.Hour},
+}
+
+type TaskService struct{}
+
+// TaskQuery 任务列表的筛选条件
+type TaskQuery struct {
+	Due           string         // today、overdue、week，为空时不限
+	Location      *time.Location // 按该时区计算“今天”
+	ApplicationID *uint
+	Completed     *bool // 为空时返回全部，默认只看未完成的由调用方决定
+}
+
+// TaskItem 任务及关联申请的公司、职位
+type TaskItem struct {
+	model.Task
+	Company  string `json:"company,omitempty"`
+	Position string `json:"position,omitempty"`
+}
+
+// TaskInput 新建任务的参数
+type TaskInput struct {
+	Title         string     `json:"title" binding:"required"`
+	Notes         string     `json:"notes"`
+	DueAt         *time.Time `json:"due_at"`
+	ApplicationID *uint      `json:"application_id"`
+}
+
+// TaskUpdate 修改任务的参数，未提供的字段保持不变
+type TaskUpdate struct {
+	Title     *string    `json:"title"`
+	Notes     *string    `json:"notes"`
+	DueAt     *time.Time `json:"due_at"`
+	ClearDue  bool       `json:"clear_due"` // 清除截止时间
+	Completed *bool      `json:"completed"`
+}
+
+// ListTasks 获取操作者的任务，未设置截止时间的排在最后
+func (s *TaskService) ListTasks(p Principal, q TaskQuery) ([]TaskItem, error) {
+	if q.Location == nil {
+		q.Location = time.Local
+	}
+	now := time.Now()
+	today := bucketStart(now, IntervalDay, q.Location)
+
+	db := database.DB.Table("tasks AS t").
+		Select("t.*, a.company, a.position").
+		Joins("LEFT JOIN applications a ON a.id = t.application_id AND a.deleted_at IS NULL").
+		Where("t.user_id = ?", p.UserID)
+
+	switch q.Due {
+	case "":
+	case TaskDueToday:
+		db = db.Where("t.due_at >= ? AND t.due_at < ?", today, today.AddDate(0, 0, 1))
+	case TaskDueOverdue:
+		db = db.Where("t.due_at < ? AND t.completed = ?", now, false)
+	case TaskDueWeek:
+		db = db.Where("t.due_at >= ? AND t.due_at < ?", today, today.AddDate(0, 0, 7))
+	default:
+		return nil, ErrInvalidDue
+	}
+	if q.ApplicationID != nil {
+		db = db.Where("t.application_id = ?", *q.ApplicationID)
+	}
+	if q.Completed != nil {
+		db = db.Where("t.completed = ?", *q.Completed)
+	}
+
+	var tasks []TaskItem
+	if err := db.Order("t.due_at IS NULL, t.due_at ASC, t.id ASC").
+		Limit(maxTaskList).
+		Scan(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// CreateTask 新建任务，关联的申请需属于操作者
+func (s *TaskService) CreateTask(p Principal, input TaskInput) (*model.Task, error) {
+	input.Title = strings.TrimSpace(input.Title)
+	if input.Title == "" || utf8.RuneCountInString(input.Title) > 128 {
+		return nil, ErrInvalidTask
+	}
+
+	task := model.Task{
+		UserID:        p.UserID,
+		ApplicationID: input.ApplicationID,
+		Title:         input.Title,
+		Notes:         input.Notes,
+		DueAt:         input.DueAt,
+		Source:        model.TaskSourceUser,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if task.ApplicationID != nil {
+			if _, err := findOwnedApplication(tx, p, *task.ApplicationID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionTaskCreate, model.AuditTargetTask, task.ID, p.UserID, nil, &task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask 修改任务或标记完成
+func (s *TaskService) UpdateTask(p Principal, id uint, req TaskUpdate) (*model.Task, error) {
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" || utf8.RuneCountInString(title) > 128 {
+			return nil, ErrInvalidTask
+		}
+		updates["title"] = title
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+	// 截止时间变化后重新提醒
+	if req.ClearDue {
+		updates["due_at"] = nil
+		updates["reminded_at"] = nil
+	} else if req.DueAt != nil {
+		updates["due_at"] = *req.DueAt
+		updates["reminded_at"] = nil
+	}
+
+	var after model.Task
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedTask(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if req.Completed != nil && *req.Completed != before.Completed {
+			updates["completed"] = *req.Completed
+			if *req.Completed {
+				updates["completed_at"] = time.Now()
+			} else {
+				updates["completed_at"] = nil
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&model.Task{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionTaskUpdate, model.AuditTargetTask, id, p.UserID, before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// DeleteTask 删除任务
+func (s *TaskService) DeleteTask(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		task, err := findOwnedTask(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(task).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionTaskDelete, model.AuditTargetTask, id, p.UserID, task, nil)
+	})
+}
+
+func findOwnedTask(tx *gorm.DB, p Principal, id uint) (*model.Task, error) {
+	var task model.Task
+	if err := tx.Where("id = ? AND user_id = ?", id, p.UserID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// createAutoTasks 为申请创建跟进任务，trigger 标识触发来源，同一来源重复触发时不会重复创建
+func createAutoTasks(tx *gorm.DB, p Principal, application *model.Application, trigger string, base time.Time, templates []taskTemplate) error {
+	for _, t := range templates {
+		key := trigger + ":" + t.kind
+		due := base.Add(t.after)
+		task := model.Task{
+			UserID:        application.UserID,
+			ApplicationID: &application.ID,
+			Title:         fmt.Sprintf(t.title, application.Company),
+			DueAt:         &due,
+			Source:        model.TaskSourceAuto,
+			AutoKey:       &key,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&task)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := recordAudit(tx, p, model.AuditActionTaskCreate, model.AuditTargetTask, task.ID, application.UserID, nil, &task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendTaskReminders 为即将到期且未完成的任务发送提醒，每个任务只提醒一次，由后台任务调用。
+// 与周报一样跳过被禁用和申请注销的用户；关联的申请在回收站中时不提醒，恢复后再提醒
+func (s *TaskService) SendTaskReminders() error {
+	var tasks []model.Task
+	if err := database.DB.
+		Joins("JOIN users ON users.id = tasks.user_id AND users.disabled = ? AND users.deletion_requested_at IS NULL", false).
+		Joins("LEFT JOIN applications ON applications.id = tasks.application_id").
+		Where("tasks.completed = ? AND tasks.reminded_at IS NULL AND tasks.due_at <= ?", false, time.Now().Add(taskReminderLead)).
+		Where("tasks.application_id IS NULL OR applications.deleted_at IS NULL").
+		Order("tasks.due_at ASC").
+		Limit(taskReminderBatch).
+		Find(&tasks).Error; err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		// 单个任务失败不影响其他任务，下次任务执行时重试
+		sub, err := findSubscription(database.DB, task.UserID)
+		if err != nil {
+			log.Printf("任务 %d 到期提醒失败: %v", task.ID, err)
+			continue
+		}
+		due := task.DueAt.In(subscriptionLocation(sub.Timezone)).Format("2006-01-02 15:04")
+		if err := notify(task.UserID, model.NotificationTaskDue, "待办到期："+task.Title, "截止时间 "+due); err != nil {
+			log.Printf("任务 %d 到期提醒失败: %v", task.ID, err)
+			continue
+		}
+		if err := database.DB.Model(&model.Task{}).Where("id = ?", task.ID).
+			UpdateColumn("reminded_at", time.Now()).Error; err != nil {
+			log.Printf("记录任务 %d 的提醒时间失败: %v", task.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 时间序列的统计粒度
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week" // 周一为一周的开始
+	IntervalMonth = "month"
+)
+
+// 时间序列指标
+const (
+	MetricApplications = "applications" // 新投递（申请创建时间）
+	MetricResponses    = "responses"    // 收到回复：申请第一次离开"已投递"状态（不含系统自动处理）
+	MetricInterviews   = "interviews"   // 已进行的面试：类型为面试且已完成的日程，按计划时间统计
+	MetricOffers       = "offers"       // 录用
+	MetricRejections   = "rejections"   // 被拒
+	MetricGhosted      = "ghosted"      // 长期无回复
+)
+
+// 单次查询最多返回的时间段数
+const maxTimeseriesBuckets = 400
+
+var (
+	ErrInvalidMetric   = errors.New("无效的统计指标")
+	ErrInvalidInterval = errors.New("无效的统计粒度")
+	ErrInvalidRange    = errors.New("无效的时间范围")
+)
+
+// 基于状态历史的指标对应的目标状态
+var metricToStatus = map[string]model.ApplicationStatus{
+	MetricOffers:     model.StatusAccepted,
+	MetricRejections: model.StatusRejected,
+	MetricGhosted:    model.StatusGhosted,
+}
+
+// TimeseriesQuery 时间序列查询参数
+type TimeseriesQuery struct {
+	Metrics  []string
+	Interval string
+	Location *time.Location // 按用户时区划分日、周、月
+	From     *time.Time     // 为空时默认最近 30 天、12 周或 12 个月
+	To       *time.Time     // 上限（不包含），为空时到当前时间段结束
+	Filter   AnalyticsFilter
+}
+
+// TimeseriesPoint 一个时间段的计数
+type TimeseriesPoint struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// Timeseries 各指标的时间序列，没有数据的时间段补零
+type Timeseries struct {
+	Interval string                       `json:"interval"`
+	Timezone string                       `json:"tz"`
+	From     time.Time                    `json:"from"`
+	To       time.Time                    `json:"to"`
+	Series   map[string][]TimeseriesPoint `json:"series"`
+}
+
+// GetTimeseries 按时间段统计投递、回复、面试等数量
+func (s *AnalyticsService) GetTimeseries(p Principal, q TimeseriesQuery) (*Timeseries, error) {
+	if len(q.Metrics) == 0 {
+		return nil, ErrInvalidMetric
+	}
+	for _, metric := range q.Metrics {
+		if _, ok := metricToStatus[metric]; !ok && metric != MetricApplications && metric != MetricResponses && metric != MetricInterviews {
+			return nil, ErrInvalidMetric
+		}
+	}
+	if q.Interval != IntervalDay && q.Interval != IntervalWeek && q.Interval != IntervalMonth {
+		return nil, ErrInvalidInterval
+	}
+	if q.Location == nil {
+		q.Location = time.Local
+	}
+
+	from, to, err := timeseriesRange(q)
+	if err != nil {
+		return nil, err
+	}
+
+	// 日期范围作用于各指标自身的时间，而不是申请的创建时间
+	q.Filter.From, q.Filter.To = nil, nil
+	base, err := analyticsApplications(p, q.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Timeseries{
+		Interval: q.Interval,
+		Timezone: q.Location.String(),
+		From:     from,
+		To:       to,
+		Series:   make(map[string][]TimeseriesPoint),
+	}
+	for _, metric := range q.Metrics {
+		times, err := metricTimes(p, base.Session(&gorm.Session{}), metric, from, to)
+		if err != nil {
+			return nil, err
+		}
+		result.Series[metric] = bucketize(times, q.Interval, q.Location, from, to)
+	}
+	return result, nil
+}
+
+// timeseriesRange 计算对齐到时间段边界的查询范围 [from, to)
+func timeseriesRange(q TimeseriesQuery) (time.Time, time.Time, error) {
+	now := time.Now().In(q.Location)
+
+	to := nextBucket(bucketStart(now, q.Interval, q.Location), q.Interval)
+	if q.To != nil {
+		// 上限不包含，最后一个时间段以 To 之前的时刻为准
+		to = nextBucket(bucketStart(q.To.Add(-time.Nanosecond), q.Interval, q.Location), q.Interval)
+	}
+
+	var from time.Time
+	if q.From != nil {
+		from = bucketStart(*q.From, q.Interval, q.Location)
+	} else {
+		from = to
+		defaults := map[string]int{IntervalDay: 30, IntervalWeek: 12, IntervalMonth: 12}
+		for i := 0; i < defaults[q.Interval]; i++ {
+			from = prevBucket(from, q.Interval)
+		}
+	}
+
+	if !from.Before(to) {
+		return from, to, ErrInvalidRange
+	}
+	buckets := 0
+	for t := from; t.Before(to); t = nextBucket(t, q.Interval) {
+		if buckets++; buckets > maxTimeseriesBuckets {
+			return from, to, ErrInvalidRange
+		}
+	}
+	return from, to, nil
+}
+
+// metricTimes 查询指标在 [from, to) 内发生的时间点
+func metricTimes(p Principal, applications *gorm.DB, metric string, from, to time.Time) ([]time.Time, error) {
+	var times []time.Time
+	switch metric {
+	case MetricApplications:
+		err := applications.
+			Where("applications.created_at >= ? AND applications.created_at < ?", from, to).
+			Pluck("applications.created_at", &times).Error
+		return times, err
+
+	case MetricResponses:
+		// 每条申请只统计第一次离开"已投递"状态的时间，长期无进展被系统自动处理的不算回复
+		var rows []struct {
+			FirstAt time.Time
+		}
+		err := database.DB.Model(&model.ApplicationHistory{}).
+			Select("MIN(created_at) AS first_at").
+			Where("user_id = ? AND action = ? AND from_status = ?", p.UserID, model.HistoryStatus, model.StatusSubmitted).
+			Where("source <> ?", model.HistorySourceSystem).
+			Where("application_id IN (?)", applications.Select("applications.id")).
+			Group("application_id").
+			Having("MIN(created_at) >= ? AND MIN(created_at) < ?", from, to).
+			Scan(&rows).Error
+		for _, row := range rows {
+			times = append(times, row.FirstAt)
+		}
+		return times, err
+
+	case MetricInterviews:
+		// 一条申请可能有多轮面试，每个已完成的面试日程各计一次
+		err := database.DB.Model(&model.ApplicationEvent{}).
+			Where("user_id = ? AND type = ? AND done_at IS NOT NULL", p.UserID, model.EventInterview).
+			Where("scheduled_at >= ? AND scheduled_at < ?", from, to).
+			Where("application_id IN (?)", applications.Select("applications.id")).
+			Pluck("scheduled_at", &times).Error
+		return times, err
+
+	default:
+		err := database.DB.Model(&model.ApplicationHistory{}).
+			Where("user_id = ? AND action = ? AND to_status = ?", p.UserID, model.HistoryStatus, metricToStatus[metric]).
+			Where("created_at >= ? AND created_at < ?", from, to).
+			Where("application_id IN (?)", applications.Select("applications.id")).
+			Pluck("created_at", &times).Error
+		return times, err
+	}
+}
+
+// bucketize 按时间段计数，没有数据的时间段补零
+func bucketize(times []time.Time, interval string, loc *time.Location, from, to time.Time) []TimeseriesPoint {
+	counts := make(map[int64]int)
+	for _, t := range times {
+		counts[bucketStart(t, interval, loc).Unix()]++
+	}
+
+	points := []TimeseriesPoint{}
+	for start := from; start.Before(to); start = nextBucket(start, interval) {
+		points = append(points, TimeseriesPoint{Start: start, Count: counts[start.Unix()]})
+	}
+	return points
+}
+
+// bucketStart 时间点所在时间段的开始时间（用户时区）
+func bucketStart(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	y, m, d := t.Date()
+	switch interval {
+	case IntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // 周一为 0
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, loc)
+	case IntervalMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+}
+
+func nextBucket(start time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func prevBucket(start time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return start.AddDate(0, 0, -7)
+	case IntervalMonth:
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
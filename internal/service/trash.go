@@ -0,0 +1,151 @@
+package service
+
+import (
+	"internship-manager/internal/model"
+	"internship-manager/pkg/database"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashItem 回收站中的申请记录
+type TrashItem struct {
+	model.Application
+	PurgeAt time.Time `json:"purge_at"` // 预计自动永久删除的时间
+}
+
+// ListTrash 分页获取回收站中的申请记录，最近删除的在前
+func (s *ApplicationService) ListTrash(p Principal, page, pageSize int) ([]TrashItem, int64, error) {
+	var applications []model.Application
+	var total int64
+
+	query := database.DB.Unscoped().Model(&model.Application{}).
+		Scopes(ownedApplications(p)).
+		Where("deleted_at IS NOT NULL")
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("deleted_at DESC").Limit(pageSize).Offset(offset).Find(&applications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]TrashItem, len(applications))
+	for i, app := range applications {
+		items[i] = TrashItem{Application: app, PurgeAt: app.DeletedAt.Time.Add(config.TrashRetention)}
+	}
+	return items, total, nil
+}
+
+// RestoreApplication 从回收站恢复申请记录
+func (s *ApplicationService) RestoreApplication(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		application, err := findOwnedDeletedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		return restoreApplicationTx(tx, p, model.HistorySourceUser, application)
+	})
+}
+
+// restoreApplicationTx 从回收站恢复申请，调用方需已完成归属校验
+func restoreApplicationTx(tx *gorm.DB, p Principal, source string, application *model.Application) error {
+	if err := updateApplicationVersioned(tx.Unscoped(), application, map[string]interface{}{"deleted_at": nil}); err != nil {
+		return err
+	}
+	if err := recordHistory(tx, p, source, model.ApplicationHistory{
+		ApplicationID: application.ID,
+		UserID:        application.UserID,
+		Action:        model.HistoryRestore,
+	}); err != nil {
+		return err
+	}
+	return recordAudit(tx, p, model.AuditActionApplicationRestore, model.AuditTargetApplication, application.ID, application.UserID, nil, nil)
+}
+
+// PurgeApplication 永久删除回收站中的申请记录
+func (s *ApplicationService) PurgeApplication(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		application, err := findOwnedDeletedApplication(tx, p, id)
+		if err != nil {
+			return err
+		}
+		return purgeApplication(tx, p, application)
+	})
+}
+
+// EmptyTrash 清空回收站，返回永久删除的条数
+func (s *ApplicationService) EmptyTrash(p Principal) (int, error) {
+	count := 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var applications []model.Application
+		if err := tx.Unscoped().Scopes(ownedApplications(p)).
+			Where("deleted_at IS NOT NULL").
+			Find(&applications).Error; err != nil {
+			return err
+		}
+
+		for i := range applications {
+			if err := purgeApplication(tx, p, &applications[i]); err != nil {
+				return err
+			}
+		}
+		count = len(applications)
+		return nil
+	})
+	return count, err
+}
+
+// PurgeExpiredTrash 永久删除超过保留期的回收站记录，由后台任务调用
+func (s *ApplicationService) PurgeExpiredTrash() error {
+	var applications []model.Application
+	if err := database.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", time.Now().Add(-config.TrashRetention)).
+		Limit(1000).
+		Find(&applications).Error; err != nil {
+		return err
+	}
+
+	for i := range applications {
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return purgeApplication(tx, SystemPrincipal, &applications[i])
+		}); err != nil {
+			// 单条失败不影响其余记录，下次任务运行时重试
+			log.Printf("永久删除回收站申请 %d 失败: %v", applications[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// purgeApplication 物理删除申请记录，调用方需已完成归属校验
+func purgeApplication(tx *gorm.DB, p Principal, application *model.Application) error {
+	for _, dependent := range applicationOwnedModels() {
+		if err := tx.Where("application_id = ?", application.ID).Delete(dependent).Error; err != nil {
+			return err
+		}
+	}
+	// 已发布的面经保留，只解除与申请的关联
+	if err := tx.Model(&model.Experience{}).Where("application_id = ?", application.ID).
+		Update("application_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Delete(&model.Application{}, application.ID).Error; err != nil {
+		return err
+	}
+	return recordAudit(tx, p, model.AuditActionApplicationPurge, model.AuditTargetApplication, application.ID, application.UserID, application, nil)
+}
+
+// applicationOwnedModels 按 application_id 归属于申请的数据表，永久删除申请时需要一并删除，
+// 合并申请时迁移到保留的记录
+func applicationOwnedModels() []interface{} {
+	return []interface{}{
+		&model.ApplicationTag{},
+		&model.ApplicationHistory{},
+		&model.InterviewQuestion{},
+		&model.ApplicationEvent{},
+		&model.Task{},
+	}
+}
@@ -6,21 +6,22 @@ import (
 	"internship-manager/pkg/database"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService struct{}
 
-// Register 用户注册
-func (s *UserService) Register(username, password, email string) error {
-	// 检查用户名是否已存在
+// Register 用户注册，p 中只有 IP 和请求ID，操作者即新注册的用户
+func (s *UserService) Register(p Principal, username, password, email string) error {
+	// 检查用户名是否已存在（唯一索引包含已软删除的行，这里也要一并检查）
 	var existingUser model.User
-	result := database.DB.Where("username = ?", username).First(&existingUser)
+	result := database.DB.Unscoped().Where("username = ?", username).First(&existingUser)
 	if result.RowsAffected > 0 {
 		return errors.New("用户名已存在")
 	}
 
 	// 检查邮箱是否已存在
-	result = database.DB.Where("email = ?", email).First(&existingUser)
+	result = database.DB.Unscoped().Where("email = ?", email).First(&existingUser)
 	if result.RowsAffected > 0 {
 		return errors.New("邮箱已被使用")
 	}
@@ -38,7 +39,13 @@ func (s *UserService) Register(username, password, email string) error {
 		Email:    email,
 	}
 
-	return database.DB.Create(&user).Error
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		p.UserID = user.ID
+		return recordAudit(tx, p, model.AuditActionUserRegister, model.AuditTargetUser, user.ID, user.ID, nil, &user)
+	})
 }
 
 // Login 用户登录
@@ -55,6 +62,10 @@ func (s *UserService) Login(username, password string) (*model.User, error) {
 		return nil, errors.New("密码错误")
 	}
 
+	if user.Disabled {
+		return nil, errors.New("账号已被禁用")
+	}
+
 	return &user, nil
 }
 
@@ -72,15 +83,23 @@ func (s *UserService) LoginByEmail(email, password string) (*model.User, error)
 		return nil, errors.New("密码错误")
 	}
 
+	if user.Disabled {
+		return nil, errors.New("账号已被禁用")
+	}
+
 	return &user, nil
 }
 
 // GetUserByID 根据ID获取用户信息
-func (s *UserService) GetUserByID(id uint) (*model.User, error) {
+func (s *UserService) GetUserByID(p Principal, id uint) (*model.User, error) {
+	if err := authorizeUser(p, id); err != nil {
+		return nil, err
+	}
+
 	var user model.User
 	result := database.DB.First(&user, id)
 	if result.Error != nil {
-		return nil, errors.New("用户不存在")
+		return nil, ErrUserNotFound
 	}
 	return &user, nil
 }
@@ -89,20 +108,63 @@ func NewUserService() *UserService {
 	return &UserService{}
 }
 
-// UpdateUser 更新用户信息
-func (s *UserService) UpdateUser(id uint, userData map[string]interface{}) error {
-	// 不允许更新用户名和密码
-	delete(userData, "username")
-	delete(userData, "password")
+// ProfileUpdate 可由用户自行修改的个人信息，字段为 nil 表示不修改
+type ProfileUpdate struct {
+	Age    *int    `json:"age" binding:"omitempty,min=16,max=100"`
+	Gender *string `json:"gender" binding:"omitempty,oneof=male female other"`
+	Phone  *string `json:"phone" binding:"omitempty,e164"`
 
-	result := database.DB.Model(&model.User{}).Where("id = ?", id).Updates(userData)
-	if result.Error != nil {
-		return result.Error
+	InsightsOptOut *bool `json:"insights_opt_out"`
+}
+
+// UpdateProfile 更新用户个人信息并返回更新后的资料，expectedVersion 不为 nil 时校验版本号
+func (s *UserService) UpdateProfile(p Principal, id uint, req ProfileUpdate, expectedVersion *uint) (*model.User, error) {
+	if err := authorizeUser(p, id); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Age != nil {
+		updates["age"] = *req.Age
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("用户不存在")
+	if req.Gender != nil {
+		updates["gender"] = *req.Gender
 	}
-	return nil
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.InsightsOptOut != nil {
+		updates["insights_opt_out"] = *req.InsightsOptOut
+	}
+
+	var after model.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var before model.User
+		if err := tx.First(&before, id).Error; err != nil {
+			return ErrUserNotFound
+		}
+		if err := checkVersion(expectedVersion, before.Version, &before); err != nil {
+			return err
+		}
+
+		if len(updates) > 0 {
+			current := before
+			if err := updateUserVersioned(tx, &current, updates); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+
+		return recordAudit(tx, p, model.AuditActionUserUpdate, model.AuditTargetUser, id, id, &before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &after, nil
 }
 
 //// UpdatePassword 更新用户密码
@@ -125,15 +187,3 @@ func (s *UserService) UpdateUser(id uint, userData map[string]interface{}) error
 //	// 更新密码
 //	return database.DB.Model(&user).Update("password", user.Password).Error
 //}
-
-// DeleteUser 删除用户
-func (s *UserService) DeleteUser(id uint) error {
-	result := database.DB.Delete(&model.User{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("用户不存在")
-	}
-	return nil
-}
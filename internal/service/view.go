@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"internship-manager/internal/filter"
+	"internship-manager/internal/model"
+	"internship-manager/internal/search"
+	"internship-manager/pkg/database"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 每个用户最多保存的视图数
+const maxViewsPerUser = 50
+
+var (
+	ErrViewNotFound  = errors.New("视图不存在")
+	ErrViewNameTaken = errors.New("视图名称已存在")
+	ErrInvalidView   = errors.New("视图名称不能为空且不超过64个字符")
+	ErrViewLimit     = errors.New("保存的视图数量已达上限")
+)
+
+type ViewService struct{}
+
+// ViewInput 新建或修改视图的参数
+type ViewInput struct {
+	Name     string `json:"name" binding:"required"`
+	Filter   string `json:"filter" binding:"max=1024"`
+	Search   string `json:"search" binding:"max=256"`
+	Sort     string `json:"sort"`
+	Desc     bool   `json:"desc"`
+	Archived bool   `json:"archived"`
+	Pinned   bool   `json:"pinned"`
+}
+
+// SharedView 分享出去的视图定义，不包含创建者信息
+type SharedView struct {
+	Name     string `json:"name"`
+	Filter   string `json:"filter"`
+	Search   string `json:"search"`
+	Sort     string `json:"sort"`
+	Desc     bool   `json:"desc"`
+	Archived bool   `json:"archived"`
+}
+
+// ListViews 获取操作者的全部视图，置顶的在前
+func (s *ViewService) ListViews(p Principal) ([]model.SavedView, error) {
+	var views []model.SavedView
+	if err := database.DB.Where("user_id = ?", p.UserID).
+		Order("pinned DESC, name ASC").
+		Find(&views).Error; err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// GetView 获取操作者的某个视图
+func (s *ViewService) GetView(p Principal, id uint) (*model.SavedView, error) {
+	return findOwnedView(database.DB, p, id)
+}
+
+// CreateView 新建视图
+func (s *ViewService) CreateView(p Principal, input ViewInput) (*model.SavedView, error) {
+	if err := validateViewInput(&input); err != nil {
+		return nil, err
+	}
+
+	view := model.SavedView{
+		UserID:   p.UserID,
+		Name:     input.Name,
+		Filter:   input.Filter,
+		Search:   input.Search,
+		Sort:     input.Sort,
+		SortDesc: input.Desc,
+		Archived: input.Archived,
+		Pinned:   input.Pinned,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.SavedView{}).Where("user_id = ?", p.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxViewsPerUser {
+			return ErrViewLimit
+		}
+		if err := checkViewName(tx, p, input.Name, 0); err != nil {
+			return err
+		}
+		if err := tx.Create(&view).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionViewCreate, model.AuditTargetView, view.ID, p.UserID, nil, &view)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// UpdateView 修改视图
+func (s *ViewService) UpdateView(p Principal, id uint, input ViewInput) (*model.SavedView, error) {
+	if err := validateViewInput(&input); err != nil {
+		return nil, err
+	}
+
+	var after model.SavedView
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedView(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := checkViewName(tx, p, input.Name, id); err != nil {
+			return err
+		}
+		if err := tx.Model(&model.SavedView{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"name":      input.Name,
+			"filter":    input.Filter,
+			"search":    input.Search,
+			"sort":      input.Sort,
+			"sort_desc": input.Desc,
+			"archived":  input.Archived,
+			"pinned":    input.Pinned,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionViewUpdate, model.AuditTargetView, id, p.UserID, before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// DeleteView 删除视图
+func (s *ViewService) DeleteView(p Principal, id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		view, err := findOwnedView(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(view).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionViewDelete, model.AuditTargetView, id, p.UserID, view, nil)
+	})
+}
+
+// ShareView 生成视图的分享令牌，已分享时返回原令牌
+func (s *ViewService) ShareView(p Principal, id uint) (*model.SavedView, error) {
+	return s.setShareToken(p, id, true)
+}
+
+// UnshareView 取消分享，原令牌失效
+func (s *ViewService) UnshareView(p Principal, id uint) (*model.SavedView, error) {
+	return s.setShareToken(p, id, false)
+}
+
+func (s *ViewService) setShareToken(p Principal, id uint, share bool) (*model.SavedView, error) {
+	var after model.SavedView
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := findOwnedView(tx, p, id)
+		if err != nil {
+			return err
+		}
+		if (before.ShareToken != nil) == share {
+			after = *before
+			return nil
+		}
+
+		var token *string
+		if share {
+			t, _, err := newVerificationToken()
+			if err != nil {
+				return err
+			}
+			token = &t
+		}
+		if err := tx.Model(&model.SavedView{}).Where("id = ?", id).Update("share_token", token).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&after, id).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, p, model.AuditActionViewUpdate, model.AuditTargetView, id, p.UserID, before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// GetSharedView 通过分享令牌查看视图定义
+func (s *ViewService) GetSharedView(token string) (*SharedView, error) {
+	view, err := findSharedView(database.DB, token)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedView{
+		Name:     view.Name,
+		Filter:   view.Filter,
+		Search:   view.Search,
+		Sort:     view.Sort,
+		Desc:     view.SortDesc,
+		Archived: view.Archived,
+	}, nil
+}
+
+// CopySharedView 将别人分享的视图复制到自己的视图中，name 为空时沿用原名称
+func (s *ViewService) CopySharedView(p Principal, token, name string) (*model.SavedView, error) {
+	shared, err := s.GetSharedView(token)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = shared.Name
+	}
+	return s.CreateView(p, ViewInput{
+		Name:     name,
+		Filter:   shared.Filter,
+		Search:   shared.Search,
+		Sort:     shared.Sort,
+		Desc:     shared.Desc,
+		Archived: shared.Archived,
+	})
+}
+
+// validateViewInput 校验视图名称、筛选表达式、检索条件和排序字段
+func validateViewInput(input *ViewInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > 64 {
+		return ErrInvalidView
+	}
+	if err := filter.Validate(input.Filter); err != nil {
+		return err
+	}
+	if input.Search != "" {
+		if _, err := search.Parse(input.Search); err != nil {
+			return err
+		}
+	}
+	if input.Sort != "" {
+		if _, ok := sortColumns[input.Sort]; !ok {
+			return ErrInvalidSort
+		}
+	}
+	return nil
+}
+
+// checkViewName 检查视图名称是否与操作者的其它视图重复
+func checkViewName(tx *gorm.DB, p Principal, name string, excludeID uint) error {
+	var count int64
+	if err := tx.Model(&model.SavedView{}).
+		Where("user_id = ? AND name = ? AND id <> ?", p.UserID, name, excludeID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrViewNameTaken
+	}
+	return nil
+}
+
+func findOwnedView(tx *gorm.DB, p Principal, id uint) (*model.SavedView, error) {
+	var view model.SavedView
+	if err := tx.Where("id = ? AND user_id = ?", id, p.UserID).First(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrViewNotFound
+		}
+		return nil, err
+	}
+	return &view, nil
+}
+
+func findSharedView(tx *gorm.DB, token string) (*model.SavedView, error) {
+	var view model.SavedView
+	if token == "" {
+		return nil, ErrViewNotFound
+	}
+	if err := tx.Where("share_token = ?", token).First(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrViewNotFound
+		}
+		return nil, err
+	}
+	return &view, nil
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"internship-manager/internal/idempotency"
+	"internship-manager/internal/job"
 	"internship-manager/internal/middleware"
 	"internship-manager/internal/router"
+	"internship-manager/internal/search"
+	"internship-manager/internal/service"
 	"internship-manager/pkg/database"
+	"internship-manager/pkg/mailer"
+	"internship-manager/pkg/pdf"
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 func getEnv(key, defaultValue string) string {
@@ -16,6 +23,19 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt 读取整数环境变量，未设置时使用默认值，无法解析或小于 min 时终止启动
+func getEnvInt(key string, defaultValue, min int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min {
+		log.Fatalf("Invalid %s=%q: must be an integer >= %d", key, raw, min)
+	}
+	return value
+}
+
 func main() {
 	// 从环境变量获取数据库配置
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "8083"))
@@ -43,6 +63,72 @@ func main() {
 	jwtKey := getEnv("JWT_KEY", "winter-key")
 	middleware.InitJWT(jwtKey)
 
+	// 从环境变量获取邮件配置，未配置 SMTP_HOST 时邮件只输出到日志
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	mailer.InitSMTP(&mailer.SMTPConfig{
+		Host:     getEnv("SMTP_HOST", ""),
+		Port:     smtpPort,
+		Username: getEnv("SMTP_USER", ""),
+		Password: getEnv("SMTP_PASSWORD", ""),
+		From:     getEnv("SMTP_FROM", "noreply@example.com"),
+		LogBody:  getEnv("MAIL_LOG_BODY", "") == "true",
+	})
+
+	deletionGraceDays := getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 14, 0)
+	exportLinkTTLHours := getEnvInt("EXPORT_LINK_TTL_HOURS", 24, 1)
+	trashRetentionDays := getEnvInt("TRASH_RETENTION_DAYS", 30, 1)
+	insightsMinUsers := getEnvInt("INSIGHTS_MIN_USERS", 5, 2)
+	accountPurgeMode := getEnv("ACCOUNT_PURGE_MODE", service.PurgeModeDelete)
+	if accountPurgeMode != service.PurgeModeDelete && accountPurgeMode != service.PurgeModeAnonymize {
+		log.Fatalf("Invalid ACCOUNT_PURGE_MODE=%q: must be %q or %q", accountPurgeMode, service.PurgeModeDelete, service.PurgeModeAnonymize)
+	}
+
+	// PDF 中文字体：PDF_FONT_PATH 指定 TrueType 字体文件，未指定时在系统字体目录中查找
+	pdfFontPath := getEnv("PDF_FONT_PATH", "")
+	pdfFont, err := pdf.FindFont(pdfFontPath)
+	if err != nil {
+		if pdfFontPath != "" {
+			log.Fatalf("Failed to load PDF_FONT_PATH: %v", err)
+		}
+		log.Printf("No embeddable CJK font found, PDF reports will reference STSong-Light without embedding it")
+	}
+	service.InitConfig(service.Config{
+		AppBaseURL:           getEnv("APP_BASE_URL", "http://localhost:8080"),
+		AccountDeletionGrace: time.Duration(deletionGraceDays) * 24 * time.Hour,
+		AccountPurgeMode:     accountPurgeMode,
+		ExportDir:            getEnv("EXPORT_DIR", "exports"),
+		ExportLinkTTL:        time.Duration(exportLinkTTLHours) * time.Hour,
+		TrashRetention:       time.Duration(trashRetentionDays) * 24 * time.Hour,
+		InsightsMinUsers:     insightsMinUsers,
+		PDFFont:              pdfFont,
+	})
+
+	// 幂等键存储：配置 REDIS_ADDR 且 IDEMPOTENCY_STORE=redis 时使用 Redis，默认使用 MySQL
+	if getEnv("IDEMPOTENCY_STORE", "mysql") == "redis" {
+		redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+		if err := database.InitRedis(&database.RedisConfig{
+			Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       redisDB,
+		}); err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		middleware.InitIdempotency(idempotency.NewRedisStore(database.Redis))
+	} else {
+		middleware.InitIdempotency(idempotency.NewMySQLStore(database.DB))
+	}
+
+	// 全文检索后端：默认使用 MySQL FULLTEXT（ngram），SEARCH_BACKEND=memory 时使用内嵌索引
+	if getEnv("SEARCH_BACKEND", "mysql") == "memory" {
+		service.InitSearch(search.NewMemoryBackend(database.DB))
+	} else {
+		service.InitSearch(search.NewMySQLBackend(database.DB))
+	}
+
+	// 启动后台任务
+	job.RegisterDefaults()
+	job.Start()
+
 	// 设置路由
 	r := router.SetupRouter()
 
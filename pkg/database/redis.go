@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+var Redis *redis.Client
+
+// InitRedis 初始化Redis连接
+func InitRedis(config *RedisConfig) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Addr,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Failed to connect to redis: %v", err)
+		return err
+	}
+
+	Redis = client
+	return nil
+}
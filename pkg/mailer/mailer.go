@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// LogBody 未配置 SMTP 时是否把邮件正文写入日志，正文中含有验证、重置等链接，只应在本地开发时开启
+	LogBody bool
+}
+
+var config *SMTPConfig
+
+// InitSMTP 初始化SMTP配置，Host 为空时邮件只写入日志
+func InitSMTP(c *SMTPConfig) {
+	config = c
+}
+
+// Send 发送纯文本邮件
+func Send(to, subject, body string) error {
+	if config == nil || config.Host == "" {
+		if config != nil && config.LogBody {
+			log.Printf("[mailer] SMTP未配置，邮件未发送 to=%s subject=%s\n%s", to, subject, body)
+		} else {
+			log.Printf("[mailer] SMTP未配置，邮件未发送 to=%s subject=%s", to, subject)
+		}
+		return nil
+	}
+
+	msg := strings.Join([]string{
+		"From: " + config.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	return smtp.SendMail(addr, auth, config.From, []string{to}, []byte(msg))
+}
@@ -0,0 +1,434 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	ErrFontNotFound    = errors.New("未找到可嵌入的中文字体")
+	ErrUnsupportedFont = errors.New("只支持 TrueType 轮廓（glyf）的 .ttf/.ttc 字体")
+)
+
+// FontDirs 查找字体的系统目录
+var FontDirs = []string{"/usr/share/fonts", "/usr/local/share/fonts", `C:\Windows\Fonts`}
+
+// fontCandidates 按优先级排列的常见中文 TrueType 字体文件名。
+// Noto Sans CJK、思源黑体等 CFF 轮廓的 OpenType 字体不能以 FontFile2 嵌入，不在此列
+var fontCandidates = []string{
+	"wqy-microhei.ttc",
+	"wqy-zenhei.ttc",
+	"NotoSansSC-Regular.ttf",
+	"DroidSansFallbackFull.ttf",
+	"DroidSansFallback.ttf",
+	"uming.ttc",
+	"simsun.ttc",
+}
+
+// 子集中保留的表，cmap 等其余表在 CIDFontType2 中用不到
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// Font 可嵌入 PDF 的 TrueType 字体，解析后只读，可在多个文档间共用
+type Font struct {
+	name       string // PostScript 名称
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	numGlyphs  int
+	advances   []uint16 // 每个字形的宽度（字体单位）
+	loca       []uint32 // 每个字形在 glyf 中的偏移，共 numGlyphs+1 项
+	glyf       []byte
+	tables     map[string][]byte
+	cmap       map[rune]uint16
+}
+
+// FindFont 加载中文字体：path 不为空时加载该文件，否则在 FontDirs 中查找 fontCandidates
+func FindFont(path string) (*Font, error) {
+	if path != "" {
+		return LoadFont(path)
+	}
+
+	found := make(map[string]string)
+	for _, dir := range FontDirs {
+		filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() {
+				if _, ok := found[d.Name()]; !ok {
+					found[d.Name()] = p
+				}
+			}
+			return nil
+		})
+	}
+	for _, name := range fontCandidates {
+		if p, ok := found[name]; ok {
+			if font, err := LoadFont(p); err == nil {
+				return font, nil
+			}
+		}
+	}
+	return nil, ErrFontNotFound
+}
+
+// LoadFont 加载 TrueType 字体文件，字体集合（.ttc）使用其中第一个字体
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	font, err := ParseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return font, nil
+}
+
+// ParseFont 解析 TrueType 字体数据
+func ParseFont(data []byte) (*Font, error) {
+	r := fontReader(data)
+	offset := 0
+	if r.tag(0) == "ttcf" {
+		offset = int(r.u32(12))
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(r.u16(offset + 4))
+	for i := 0; i < numTables; i++ {
+		rec := offset + 12 + i*16
+		start, length := int(r.u32(rec+8)), int(r.u32(rec+12))
+		if rec+16 > len(data) || start < 0 || length < 0 || start+length > len(data) {
+			return nil, ErrUnsupportedFont
+		}
+		tables[r.tag(rec)] = data[start : start+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, ErrUnsupportedFont
+		}
+	}
+
+	head, hhea := fontReader(tables["head"]), fontReader(tables["hhea"])
+	f := &Font{
+		name:       fontName(tables["name"]),
+		unitsPerEm: int(head.u16(18)),
+		bbox:       [4]int{int(head.i16(36)), int(head.i16(38)), int(head.i16(40)), int(head.i16(42))},
+		ascent:     int(hhea.i16(4)),
+		descent:    int(hhea.i16(6)),
+		numGlyphs:  int(fontReader(tables["maxp"]).u16(4)),
+		glyf:       tables["glyf"],
+		tables:     tables,
+	}
+	if f.unitsPerEm == 0 || f.numGlyphs == 0 {
+		return nil, ErrUnsupportedFont
+	}
+
+	// 超过 numberOfHMetrics 的字形沿用最后一个宽度
+	hmtx := fontReader(tables["hmtx"])
+	numHMetrics := int(hhea.u16(34))
+	f.advances = make([]uint16, f.numGlyphs)
+	for i := range f.advances {
+		if i < numHMetrics {
+			f.advances[i] = hmtx.u16(i * 4)
+		} else if numHMetrics > 0 {
+			f.advances[i] = f.advances[numHMetrics-1]
+		}
+	}
+
+	loca := fontReader(tables["loca"])
+	f.loca = make([]uint32, f.numGlyphs+1)
+	for i := range f.loca {
+		if head.i16(50) == 0 {
+			f.loca[i] = uint32(loca.u16(i*2)) * 2
+		} else {
+			f.loca[i] = loca.u32(i * 4)
+		}
+		if int(f.loca[i]) > len(f.glyf) || (i > 0 && f.loca[i] < f.loca[i-1]) {
+			return nil, ErrUnsupportedFont
+		}
+	}
+
+	f.cmap = parseCmap(tables["cmap"])
+	if len(f.cmap) == 0 {
+		return nil, ErrUnsupportedFont
+	}
+	return f, nil
+}
+
+// glyph 字符对应的字形，字体中没有的字符使用 0 号字形（.notdef）
+func (f *Font) glyph(r rune) uint16 {
+	return f.cmap[r]
+}
+
+// width 字形宽度，换算为 PDF 文字空间的千分之一单位
+func (f *Font) width(gid uint16) int {
+	return int(f.advances[gid]) * 1000 / f.unitsPerEm
+}
+
+func (f *Font) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// subset 只保留用到的字形（及组合字形引用的部件），字形编号不变，其余字形为空
+func (f *Font) subset(used map[uint16]rune) []byte {
+	keep := map[uint16]bool{0: true}
+	var visit func(gid uint16)
+	visit = func(gid uint16) {
+		if keep[gid] {
+			return
+		}
+		keep[gid] = true
+		for _, component := range f.components(gid) {
+			visit(component)
+		}
+	}
+	for gid := range used {
+		visit(gid)
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, (f.numGlyphs+1)*4)
+	for gid := 0; gid < f.numGlyphs; gid++ {
+		binary.BigEndian.PutUint32(loca[gid*4:], uint32(glyf.Len()))
+		if keep[uint16(gid)] {
+			glyf.Write(f.glyf[f.loca[gid]:f.loca[gid+1]])
+			for glyf.Len()%4 != 0 {
+				glyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[f.numGlyphs*4:], uint32(glyf.Len()))
+
+	// 统一使用长格式 loca，校验和调整值最后重新计算
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": glyf.Bytes(), "loca": loca, "head": head}
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok && f.tables[tag] != nil {
+			tables[tag] = f.tables[tag]
+		}
+	}
+	out := writeSfnt(tables)
+	binary.BigEndian.PutUint32(out[headOffset(out)+8:], 0xB1B0AFBA-checksum(out))
+	return out
+}
+
+// components 组合字形引用的部件字形
+func (f *Font) components(gid uint16) []uint16 {
+	if int(gid) >= f.numGlyphs {
+		return nil
+	}
+	data := fontReader(f.glyf[f.loca[gid]:f.loca[gid+1]])
+	if len(data) < 10 || data.i16(0) >= 0 {
+		return nil
+	}
+
+	var components []uint16
+	for pos := 10; pos+4 <= len(data); {
+		flags := data.u16(pos)
+		components = append(components, data.u16(pos+2))
+		pos += 4
+		if flags&0x0001 != 0 { // ARG_1_AND_2_ARE_WORDS
+			pos += 4
+		} else {
+			pos += 2
+		}
+		switch {
+		case flags&0x0008 != 0: // WE_HAVE_A_SCALE
+			pos += 2
+		case flags&0x0040 != 0: // WE_HAVE_AN_X_AND_Y_SCALE
+			pos += 4
+		case flags&0x0080 != 0: // WE_HAVE_A_TWO_BY_TWO
+			pos += 8
+		}
+		if flags&0x0020 == 0 { // MORE_COMPONENTS
+			break
+		}
+	}
+	return components
+}
+
+// parseCmap 读取 Unicode 字符到字形的映射，优先使用完整 Unicode 的 format 12 子表
+func parseCmap(data []byte) map[rune]uint16 {
+	r := fontReader(data)
+	var format4, format12 int
+	for i := 0; i < int(r.u16(2)); i++ {
+		rec := 4 + i*8
+		platform, encoding, offset := r.u16(rec), r.u16(rec+2), int(r.u32(rec+4))
+		if platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+		switch r.u16(offset) {
+		case 4:
+			format4 = offset
+		case 12:
+			format12 = offset
+		}
+	}
+
+	cmap := make(map[rune]uint16)
+	switch {
+	case format12 != 0:
+		groups := int(r.u32(format12 + 12))
+		for i := 0; i < groups && format12+16+i*12 < len(data); i++ {
+			g := format12 + 16 + i*12
+			start, end, gid := r.u32(g), r.u32(g+4), r.u32(g+8)
+			for c := start; c <= end && c <= 0x10FFFF && end-start < 0x10000; c++ {
+				cmap[rune(c)] = uint16(gid + c - start)
+			}
+		}
+	case format4 != 0:
+		segCount := int(r.u16(format4+6)) / 2
+		ends := format4 + 14
+		starts := ends + segCount*2 + 2
+		deltas := starts + segCount*2
+		rangeOffsets := deltas + segCount*2
+		for i := 0; i < segCount; i++ {
+			start, end := int(r.u16(starts+i*2)), int(r.u16(ends+i*2))
+			delta, rangeOffset := int(r.u16(deltas+i*2)), int(r.u16(rangeOffsets+i*2))
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				gid := 0
+				if rangeOffset == 0 {
+					gid = (c + delta) & 0xFFFF
+				} else if g := int(r.u16(rangeOffsets + i*2 + rangeOffset + (c-start)*2)); g != 0 {
+					gid = (g + delta) & 0xFFFF
+				}
+				if gid != 0 {
+					cmap[rune(c)] = uint16(gid)
+				}
+			}
+		}
+	}
+	return cmap
+}
+
+// fontName 读取 PostScript 名称（nameID 6），只保留 PDF 名称中安全的字符
+func fontName(data []byte) string {
+	r := fontReader(data)
+	count, storage := int(r.u16(2)), int(r.u16(4))
+	for i := 0; i < count; i++ {
+		rec := 6 + i*12
+		if r.u16(rec+6) != 6 {
+			continue
+		}
+		length, offset := int(r.u16(rec+8)), storage+int(r.u16(rec+10))
+		if offset+length > len(data) {
+			continue
+		}
+		raw := data[offset : offset+length]
+		var name string
+		if platform := r.u16(rec); platform == 0 || platform == 3 {
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[j*2:])
+			}
+			name = string(utf16.Decode(units))
+		} else {
+			name = string(raw)
+		}
+		name = strings.Map(func(c rune) rune {
+			if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' {
+				return c
+			}
+			return -1
+		}, name)
+		if name != "" {
+			return name
+		}
+	}
+	return "EmbeddedFont"
+}
+
+// writeSfnt 按 TrueType 格式写出字体文件，表按标签排序并 4 字节对齐
+func writeSfnt(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	searchRange := (1 << entrySelector) * 16
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.BigEndian, []uint16{
+		0x0001, 0x0000, uint16(len(tags)), uint16(searchRange), uint16(entrySelector), uint16(len(tags)*16 - searchRange),
+	})
+	offset := 12 + len(tags)*16
+	for _, tag := range tags {
+		out.WriteString(tag)
+		binary.Write(&out, binary.BigEndian, []uint32{checksum(tables[tag]), uint32(offset), uint32(len(tables[tag]))})
+		offset += (len(tables[tag]) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		out.Write(tables[tag])
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+	return out.Bytes()
+}
+
+// headOffset 已写出的字体文件中 head 表的位置
+func headOffset(data []byte) int {
+	r := fontReader(data)
+	for i := 0; i < int(r.u16(4)); i++ {
+		if r.tag(12+i*16) == "head" {
+			return int(r.u32(12 + i*16 + 8))
+		}
+	}
+	return 0
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// fontReader 按大端序读取字体数据，越界时返回 0，由调用方校验结果
+type fontReader []byte
+
+func (r fontReader) u16(pos int) uint16 {
+	if pos < 0 || pos+2 > len(r) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(r[pos:])
+}
+
+func (r fontReader) i16(pos int) int16 {
+	return int16(r.u16(pos))
+}
+
+func (r fontReader) u32(pos int) uint32 {
+	if pos < 0 || pos+4 > len(r) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(r[pos:])
+}
+
+func (r fontReader) tag(pos int) string {
+	if pos < 0 || pos+4 > len(r) {
+		return ""
+	}
+	return string(r[pos : pos+4])
+}
@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// 测试用的 TrueType 字体，环境中没有时跳过
+const testFontPath = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+func loadTestFont(t *testing.T) *Font {
+	t.Helper()
+	font, err := LoadFont(testFontPath)
+	if err != nil {
+		t.Skipf("没有可用的测试字体: %v", err)
+	}
+	return font
+}
+
+func TestSubsetKeepsUsedGlyphs(t *testing.T) {
+	font := loadTestFont(t)
+	used := map[uint16]rune{}
+	for _, r := range "Offer 2026 é" {
+		used[font.glyph(r)] = r
+	}
+
+	data := font.subset(used)
+	if checksum(data) != 0xB1B0AFBA {
+		t.Fatalf("子集字体的校验和调整值错误")
+	}
+	tables := map[string][]byte{}
+	r := fontReader(data)
+	for i := 0; i < int(r.u16(4)); i++ {
+		rec := 12 + i*16
+		start, length := int(r.u32(rec+8)), int(r.u32(rec+12))
+		tables[r.tag(rec)] = data[start : start+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf"} {
+		if tables[tag] == nil {
+			t.Fatalf("子集缺少 %s 表", tag)
+		}
+	}
+	if len(tables["glyf"]) >= len(font.glyf) {
+		t.Fatalf("子集没有变小：%d >= %d", len(tables["glyf"]), len(font.glyf))
+	}
+
+	loca := fontReader(tables["loca"])
+	for gid := 0; gid < font.numGlyphs; gid++ {
+		start, end := loca.u32(gid*4), loca.u32(gid*4+4)
+		original := font.glyf[font.loca[gid]:font.loca[gid+1]]
+		_, isUsed := used[uint16(gid)]
+		if isUsed || gid == 0 {
+			got := tables["glyf"][start:end]
+			if !bytes.Equal(got[:len(original)], original) {
+				t.Fatalf("字形 %d 的数据被改变", gid)
+			}
+		} else if len(font.components(uint16(gid))) == 0 && end != start {
+			// 未用到的简单字形应为空；组合字形的部件可能被保留
+			if !usedAsComponent(font, used, uint16(gid)) {
+				t.Fatalf("未用到的字形 %d 没有去掉", gid)
+			}
+		}
+	}
+}
+
+func usedAsComponent(font *Font, used map[uint16]rune, gid uint16) bool {
+	for g := range used {
+		for _, c := range font.components(g) {
+			if c == gid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestEmbeddedFontDocument(t *testing.T) {
+	font := loadTestFont(t)
+	doc := New()
+	doc.SetFont(font)
+	doc.Title = "Summary"
+	doc.AddPage().Text(40, 40, 12, Black, "Offer 2026")
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"/Subtype /CIDFontType2", "/Encoding /Identity-H", "/CIDToGIDMap /Identity",
+		"/FontFile2 7 0 R", "/ToUnicode 8 0 R", "/Length1 "} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Fatalf("PDF 缺少 %q", want)
+		}
+	}
+	if bytes.Contains(buf.Bytes(), []byte("STSong-Light")) {
+		t.Fatal("嵌入字体时不应再引用 STSong-Light")
+	}
+
+	// ToUnicode 中每个用到的字形都映射回原字符
+	cmap := streamContent(t, out, 8)
+	for _, r := range "Ofer 2026" {
+		entry := fmt.Sprintf("<%04X> <%s>", font.glyph(r), encodeText(string(r)))
+		if !bytes.Contains(cmap, []byte(entry)) {
+			t.Fatalf("ToUnicode 缺少 %q: %s", entry, cmap)
+		}
+	}
+
+	// 交叉引用表中每个对象的偏移量都指向对应的对象
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(out, -1)
+	for i, m := range xref {
+		offset, _ := strconv.Atoi(m[1])
+		prefix := strconv.Itoa(i+1) + " 0 obj"
+		if offset == 0 || out[offset:offset+len(prefix)] != prefix {
+			t.Fatalf("对象 %d 的偏移量错误", i+1)
+		}
+	}
+}
+
+func TestFallbackFontDocument(t *testing.T) {
+	doc := New()
+	doc.AddPage().Text(40, 40, 12, Black, "求职总结")
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/BaseFont /STSong-Light")) || bytes.Contains(buf.Bytes(), []byte("/FontFile2")) {
+		t.Fatal("未指定字体时应使用不嵌入的 STSong-Light")
+	}
+}
+
+// streamContent 解压第 id 个对象的流
+func streamContent(t *testing.T, pdf string, id int) []byte {
+	t.Helper()
+	re := regexp.MustCompile(`(?s)\n` + strconv.Itoa(id) + ` 0 obj\n<< /Length (\d+)[^>]*>>\nstream\n`)
+	loc := re.FindStringSubmatchIndex(pdf)
+	if loc == nil {
+		t.Fatalf("找不到对象 %d", id)
+	}
+	length, _ := strconv.Atoi(pdf[loc[2]:loc[3]])
+	zr, err := zlib.NewReader(bytes.NewReader([]byte(pdf[loc[1] : loc[1]+length])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,358 @@
+// Package pdf 生成简单的 PDF 文档（文字、线条、矩形），不依赖外部程序。
+//
+// 通过 SetFont 指定 TrueType 字体时，文字以 CIDFontType2 嵌入用到的字形子集（FontFile2），
+// 并附带 ToUnicode 映射，任何阅读器都能显示，也可以复制和搜索文字。
+// 未指定字体时使用 Adobe-GB1 字符集的 STSong-Light（UniGB-UTF16-H 编码），不嵌入字体文件，
+// 依赖阅读器本地的中文字体替代显示，没有中文字体的阅读器会显示为空白或乱码。
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 纸尺寸（单位：点，1/72 英寸）
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Color RGB 颜色，各分量取值 0-1
+type Color struct {
+	R, G, B float64
+}
+
+// 常用颜色
+var (
+	Black     = Color{0, 0, 0}
+	Gray      = Color{0.5, 0.5, 0.5}
+	LightGray = Color{0.9, 0.9, 0.9}
+	White     = Color{1, 1, 1}
+)
+
+// Document PDF 文档
+type Document struct {
+	Width, Height float64
+	Title         string
+	pages         []*Page
+
+	font *Font
+	used map[uint16]rune // 用到的字形及其对应的字符，用于生成子集和 ToUnicode
+}
+
+// New 创建 A4 纵向文档
+func New() *Document {
+	return &Document{Width: A4Width, Height: A4Height}
+}
+
+// SetFont 指定嵌入的字体，需要在绘制文字之前调用；font 为 nil 时使用不嵌入的 STSong-Light
+func (d *Document) SetFont(font *Font) {
+	d.font = font
+	d.used = make(map[uint16]rune)
+}
+
+// AddPage 追加一页并返回
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d, height: d.Height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// PageCount 当前页数
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Page 一页的绘图指令。坐标以页面左上角为原点，y 轴向下
+type Page struct {
+	doc     *Document
+	height  float64
+	content bytes.Buffer
+}
+
+// Text 在 (x, y) 处绘制文字，y 为文字基线位置
+func (p *Page) Text(x, y, size float64, color Color, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT %s rg /F1 %s Tf %s %s Td <%s> Tj ET\n",
+		color.operands(), num(size), num(x), num(p.height-y), p.doc.encode(s))
+}
+
+// encode 将文字编码为当前字体的十六进制串：嵌入字体时为字形编号（Identity-H），否则为 UTF-16BE
+func (d *Document) encode(s string) string {
+	if d.font == nil {
+		return encodeText(s)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		gid := d.font.glyph(r)
+		if _, ok := d.used[gid]; !ok {
+			d.used[gid] = r
+		}
+		fmt.Fprintf(&b, "%04X", gid)
+	}
+	return b.String()
+}
+
+// Line 绘制线段
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		color.operands(), num(width), num(x1), num(p.height-y1), num(x2), num(p.height-y2))
+}
+
+// FillRect 绘制填充矩形，(x, y) 为左上角
+func (p *Page) FillRect(x, y, w, h float64, color Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		color.operands(), num(x), num(p.height-y-h), num(w), num(h))
+}
+
+// TextWidth 估算文字宽度：ASCII 字符为半角，其余为全角
+func TextWidth(s string, size float64) float64 {
+	var w float64
+	for _, r := range s {
+		if r < 0x80 {
+			w += size * 0.5
+		} else {
+			w += size
+		}
+	}
+	return w
+}
+
+// Truncate 截断文字使其宽度不超过 maxWidth，截断时以省略号结尾
+func Truncate(s string, size, maxWidth float64) string {
+	if TextWidth(s, size) <= maxWidth {
+		return s
+	}
+	ellipsis := "…"
+	limit := maxWidth - TextWidth(ellipsis, size)
+	var b strings.Builder
+	var w float64
+	for _, r := range s {
+		rw := TextWidth(string(r), size)
+		if w+rw > limit {
+			break
+		}
+		w += rw
+		b.WriteRune(r)
+	}
+	return b.String() + ellipsis
+}
+
+// WriteTo 输出 PDF 文件
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	out := &pdfWriter{}
+
+	// 对象编号：1 目录，2 页面树，3-5 字体，6 文档信息，嵌入字体时 7 字体文件、8 ToUnicode，
+	// 之后每页两个对象（页面、内容流）
+	fixedObjects := 6
+	if d.font != nil {
+		fixedObjects = 8
+	}
+	pageRefs := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageRefs[i] = fmt.Sprintf("%d 0 R", fixedObjects+1+i*2)
+	}
+
+	out.header()
+	out.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	out.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(d.pages)))
+	if d.font != nil {
+		if err := d.writeEmbeddedFont(out); err != nil {
+			return 0, err
+		}
+	} else {
+		out.object(3, "<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UTF16-H /DescendantFonts [4 0 R] >>")
+		// CID 1-95 为 ASCII 字符，按半角宽度排版
+		out.object(4, "<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> "+
+			"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>")
+		out.object(5, "<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] "+
+			"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	}
+	out.object(6, fmt.Sprintf("<< /Title <%s> /Producer (internship-manager) >>", encodeTextString(d.Title)))
+
+	for i, page := range d.pages {
+		pageID := fixedObjects + 1 + i*2
+		out.object(pageID, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", num(d.Width), num(d.Height), pageID+1))
+		if err := out.stream(pageID+1, page.content.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	out.trailer()
+
+	n, err := w.Write(out.buf.Bytes())
+	return int64(n), err
+}
+
+// writeEmbeddedFont 写出嵌入字体的对象 3-5、7、8：字形编号即 CID（Identity-H），只嵌入用到的字形
+func (d *Document) writeEmbeddedFont(out *pdfWriter) error {
+	f := d.font
+	gids := make([]int, 0, len(d.used))
+	for gid := range d.used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	// 子集字体名以6个大写字母为前缀，由用到的字形决定
+	h := fnv.New32a()
+	for _, gid := range gids {
+		fmt.Fprintf(h, "%d,", gid)
+	}
+	sum := h.Sum32()
+	var tag [6]byte
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	name := string(tag[:]) + "+" + f.name
+
+	out.object(3, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [4 0 R] /ToUnicode 8 0 R >>", name))
+	out.object(4, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor 5 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>", name, glyphWidths(f, gids)))
+	out.object(5, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 7 0 R >>",
+		name, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent)))
+
+	data := f.subset(d.used)
+	if err := out.streamWithDict(7, data, fmt.Sprintf("/Length1 %d", len(data))); err != nil {
+		return err
+	}
+	return out.stream(8, toUnicodeCMap(gids, d.used))
+}
+
+// glyphWidths W 数组：连续的字形编号合并为一组，如 3 [500 600] 10 [1000]
+func glyphWidths(f *Font, gids []int) string {
+	var b strings.Builder
+	for i := 0; i < len(gids); {
+		j := i
+		fmt.Fprintf(&b, "%d [", gids[i])
+		for ; j < len(gids) && gids[j] == gids[i]+(j-i); j++ {
+			if j > i {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "%d", f.width(uint16(gids[j])))
+		}
+		b.WriteString("] ")
+		i = j
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// toUnicodeCMap 字形编号到字符的映射，阅读器复制和搜索文字时使用
+func toUnicodeCMap(gids []int, used map[uint16]rune) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// 每个 bfchar 段最多100项
+	for i := 0; i < len(gids); i += 100 {
+		end := i + 100
+		if end > len(gids) {
+			end = len(gids)
+		}
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-i)
+		for _, gid := range gids[i:end] {
+			fmt.Fprintf(&b, "<%04X> <%s>\n", gid, encodeText(string(used[uint16(gid)])))
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// pdfWriter 按顺序写入对象并记录偏移量，用于生成交叉引用表
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *pdfWriter) header() {
+	// 第二行的二进制注释提示传输工具按二进制处理
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+}
+
+func (w *pdfWriter) begin(id int) {
+	for len(w.offsets) < id {
+		w.offsets = append(w.offsets, 0)
+	}
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", id)
+}
+
+func (w *pdfWriter) object(id int, body string) {
+	w.begin(id)
+	w.buf.WriteString(body)
+	w.buf.WriteString("\nendobj\n")
+}
+
+func (w *pdfWriter) stream(id int, data []byte) error {
+	return w.streamWithDict(id, data, "")
+}
+
+// streamWithDict 写入压缩的流对象，extra 为字典中的其他项
+func (w *pdfWriter) streamWithDict(id int, data []byte, extra string) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	w.begin(id)
+	if extra != "" {
+		extra = " " + extra
+	}
+	fmt.Fprintf(&w.buf, "<< /Length %d /Filter /FlateDecode%s >>\nstream\n", compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+func (w *pdfWriter) trailer() {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+}
+
+// encodeText 将文字编码为 UTF-16BE 十六进制串，供 UniGB-UTF16-H 编码的字体使用
+func encodeText(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
+
+// encodeTextString 文档信息中的文本字符串，带 BOM 的 UTF-16BE
+func encodeTextString(s string) string {
+	return "FEFF" + encodeText(s)
+}
+
+func (c Color) operands() string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+// num 格式化数字，去掉多余的零
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,31 @@
+package utils
+
+// BigramSimilarity 基于字符二元组的 Dice 系数，返回 0~1，适用于中英文混合的短文本
+func BigramSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	if string(ra) == string(rb) {
+		return 1
+	}
+	if len(ra) == 1 || len(rb) == 1 {
+		return 0
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < len(ra)-1; i++ {
+		counts[string(ra[i:i+2])]++
+	}
+
+	matches := 0
+	for i := 0; i < len(rb)-1; i++ {
+		bigram := string(rb[i : i+2])
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			matches++
+		}
+	}
+
+	return 2 * float64(matches) / float64(len(ra)-1+len(rb)-1)
+}
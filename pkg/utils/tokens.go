@@ -0,0 +1,26 @@
+package utils
+
+// SplitQuoted 按空白（含全角空格）切分，双引号内的空白不切分，引号保留在结果中
+func SplitQuoted(raw string) []string {
+	var tokens []string
+	var current []rune
+	inQuote := false
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			current = append(current, r)
+		case (r == ' ' || r == '\t' || r == '　') && !inQuote:
+			if len(current) > 0 {
+				tokens = append(tokens, string(current))
+				current = current[:0]
+			}
+		default:
+			current = append(current, r)
+		}
+	}
+	if len(current) > 0 {
+		tokens = append(tokens, string(current))
+	}
+	return tokens
+}
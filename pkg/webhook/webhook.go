@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL       = errors.New("webhook 地址无效")
+	ErrForbiddenAddress = errors.New("webhook 地址不能指向本机或内网")
+	errRedirect         = errors.New("webhook 不允许重定向")
+)
+
+// 签名相关的请求头：X-Webhook-Signature 为 sha256=HMAC-SHA256(secret, 时间戳 + "." + 请求体) 的十六进制
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// client 在 DNS 解析之后、建立连接之前检查目标 IP，防止通过域名指向内网；不走代理，也不跟随重定向
+var client = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: checkDialAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return errRedirect
+	},
+}
+
+// checkDialAddress 拒绝连接回环、内网、链路本地和未指定地址
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !allowedIP(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+func allowedIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// ValidateURL 保存订阅时检查地址：只允许 http/https，且主机解析出的地址都不能是本机或内网。
+// 发送时还会在连接前再检查一次，防止 DNS 记录在保存后被改为内网地址
+func ValidateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", u.Hostname())
+	if err != nil || len(ips) == 0 {
+		return ErrInvalidURL
+	}
+	for _, ip := range ips {
+		if !allowedIP(ip) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// Post 以 JSON 格式向 url 发送 payload，secret 不为空时附带签名，非 2xx 响应视为失败
+func Post(url, secret string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "internship-manager-webhook")
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(secret, timestamp, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign 计算请求签名，接收方用同样的方法验证；时间戳参与签名，接收方可据此拒绝重放的旧请求
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
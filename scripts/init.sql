@@ -14,6 +14,8 @@ CREATE TABLE IF NOT EXISTS users (
     age INT,
     gender VARCHAR(10),
     phone VARCHAR(20),
+    role VARCHAR(16) NOT NULL DEFAULT 'user',
+    disabled TINYINT(1) NOT NULL DEFAULT 0,
     last_login_at DATETIME NULL,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
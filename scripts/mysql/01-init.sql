@@ -12,6 +12,8 @@ CREATE TABLE IF NOT EXISTS users (
     age INT,
     gender VARCHAR(10),
     phone VARCHAR(20),
+    role VARCHAR(16) NOT NULL DEFAULT 'user',
+    disabled TINYINT(1) NOT NULL DEFAULT 0,
     last_login_at DATETIME NULL,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -40,6 +42,6 @@ CREATE TABLE IF NOT EXISTS applications (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- 可以添加一些初始数据（可选）
-INSERT INTO users (username, password, email) VALUES 
-('admin', '$2a$10$your_hashed_password', 'admin@example.com')
-ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP; 
\ No newline at end of file
+INSERT INTO users (username, password, email, role) VALUES 
+('admin', '$2a$10$your_hashed_password', 'admin@example.com', 'admin')
+ON DUPLICATE KEY UPDATE role = 'admin'; 
\ No newline at end of file
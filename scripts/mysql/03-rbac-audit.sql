@@ -0,0 +1,27 @@
+USE internship_manager;
+
+-- 用户角色与禁用状态（init.sql 新建的库已包含这两列，此时跳过）
+SET @has_role = (SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'role');
+SET @sql = IF(@has_role = 0,
+    'ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT ''user'' AFTER phone, ADD COLUMN disabled TINYINT(1) NOT NULL DEFAULT 0 AFTER role',
+    'DO 0');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- 初始化管理员账号
+UPDATE users SET role = 'admin' WHERE username = 'admin';
+
+-- 创建审计日志表（只追加）
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor_id BIGINT UNSIGNED NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id BIGINT UNSIGNED NULL,
+    ip VARCHAR(64),
+    INDEX idx_audit_actor_created (actor_id, created_at),
+    INDEX idx_audit_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+USE internship_manager;
+
+-- 审计日志记录数据归属、变更内容和请求ID
+ALTER TABLE audit_events
+    ADD COLUMN owner_id BIGINT UNSIGNED NULL AFTER actor_id,
+    ADD COLUMN before_data JSON NULL AFTER target_id,
+    ADD COLUMN after_data JSON NULL AFTER before_data,
+    ADD COLUMN request_id VARCHAR(64) NULL AFTER ip,
+    ADD INDEX idx_audit_owner_created (owner_id, created_at);
+
+-- 审计日志只允许追加
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
+CREATE TRIGGER audit_events_no_delete BEFORE DELETE ON audit_events
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
@@ -0,0 +1,14 @@
+USE internship_manager;
+
+-- 修改邮箱申请
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    new_email VARCHAR(128) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    consumed_at DATETIME NULL,
+    INDEX idx_email_change_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+USE internship_manager;
+
+-- 账号注销冷静期
+ALTER TABLE users
+    ADD COLUMN deletion_requested_at DATETIME NULL AFTER last_login_at,
+    ADD COLUMN deletion_scheduled_at DATETIME NULL AFTER deletion_requested_at,
+    ADD INDEX idx_users_deletion_scheduled (deletion_scheduled_at);
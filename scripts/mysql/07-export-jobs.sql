@@ -0,0 +1,18 @@
+USE internship_manager;
+
+-- 个人数据导出任务
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error VARCHAR(512),
+    file_path VARCHAR(512),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    download_token CHAR(64) NULL UNIQUE,
+    completed_at DATETIME NULL,
+    expires_at DATETIME NULL,
+    INDEX idx_export_jobs_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+USE internship_manager;
+
+-- 回收站查询与过期清理
+ALTER TABLE applications
+    ADD INDEX idx_applications_user_deleted (user_id, deleted_at),
+    ADD INDEX idx_applications_deleted (deleted_at);
@@ -0,0 +1,47 @@
+USE internship_manager;
+
+-- 申请归档
+ALTER TABLE applications
+    ADD COLUMN archived_at DATETIME NULL AFTER notes,
+    ADD INDEX idx_applications_archived (archived_at);
+
+-- 标签
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    UNIQUE INDEX idx_tags_user_name (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS application_tags (
+    application_id BIGINT UNSIGNED NOT NULL,
+    tag_id BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, tag_id),
+    INDEX idx_application_tags_tag (tag_id),
+    FOREIGN KEY (application_id) REFERENCES applications(id),
+    FOREIGN KEY (tag_id) REFERENCES tags(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 申请变更历史
+CREATE TABLE IF NOT EXISTS application_histories (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    application_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    actor_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    source VARCHAR(16) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    from_status VARCHAR(32),
+    to_status VARCHAR(32),
+    detail VARCHAR(255),
+    INDEX idx_history_application (application_id),
+    INDEX idx_history_user_created (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 为已有申请补一条创建记录，保证状态历史完整。新建的申请总是处于已投递状态，
+-- 当前状态是之后变更的结果，不能当作创建时的状态
+INSERT INTO application_histories (created_at, application_id, user_id, actor_id, source, action, to_status)
+SELECT created_at, id, user_id, user_id, 'system', 'create', 'submitted' FROM applications;
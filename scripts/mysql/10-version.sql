@@ -0,0 +1,5 @@
+USE internship_manager;
+
+-- 乐观锁版本号
+ALTER TABLE applications ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER notes;
+ALTER TABLE users ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER last_login_at;
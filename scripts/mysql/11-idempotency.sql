@@ -0,0 +1,17 @@
+USE internship_manager;
+
+-- 幂等键
+CREATE TABLE IF NOT EXISTS idempotency_records (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    idem_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    completed TINYINT(1) NOT NULL DEFAULT 0,
+    status_code INT,
+    content_type VARCHAR(128),
+    body MEDIUMBLOB,
+    UNIQUE INDEX idx_idempotency_user_key (user_id, idem_key),
+    INDEX idx_idempotency_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+USE internship_manager;
+
+-- 归一化公司名，用于重复申请检测；历史数据由后台任务 backfill-company-keys 补全
+ALTER TABLE applications
+    ADD COLUMN company_key VARCHAR(128) NOT NULL DEFAULT '' AFTER notes,
+    ADD INDEX idx_applications_user_company_key (user_id, company_key);
@@ -0,0 +1,9 @@
+USE internship_manager;
+
+-- 全文检索：ngram 解析器支持中文，默认 ngram_token_size=2
+-- MATCH 的列必须与索引定义完全一致，因此除联合索引外每个字段各建一个索引，用于 position:后端 这类限定字段的查询
+ALTER TABLE applications
+    ADD FULLTEXT INDEX ft_applications_all (company, position, notes) WITH PARSER ngram,
+    ADD FULLTEXT INDEX ft_applications_company (company) WITH PARSER ngram,
+    ADD FULLTEXT INDEX ft_applications_position (position) WITH PARSER ngram,
+    ADD FULLTEXT INDEX ft_applications_notes (notes) WITH PARSER ngram;
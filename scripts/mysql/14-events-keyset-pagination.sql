@@ -0,0 +1,33 @@
+USE internship_manager;
+
+-- 笔试、面试等日程
+CREATE TABLE IF NOT EXISTS application_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    application_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    title VARCHAR(128),
+    scheduled_at DATETIME NOT NULL,
+    link VARCHAR(256),
+    notes TEXT,
+    done_at DATETIME NULL,
+    INDEX idx_events_application (application_id, done_at, scheduled_at),
+    INDEX idx_events_user_scheduled (user_id, scheduled_at),
+    FOREIGN KEY (application_id) REFERENCES applications(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 最近一个未完成日程的时间（由日程派生），状态在流程中的先后顺序（生成列）
+ALTER TABLE applications
+    ADD COLUMN next_event_at DATETIME NULL AFTER archived_at,
+    ADD COLUMN status_rank TINYINT UNSIGNED
+        AS (FIELD(status, 'submitted', 'written', 'interview', 'accepted', 'rejected')) STORED AFTER status;
+
+-- 游标分页：每种排序一个索引，InnoDB 二级索引隐含主键 id，作为次要排序
+ALTER TABLE applications
+    ADD INDEX idx_applications_list_updated (user_id, deleted_at, archived_at, updated_at),
+    ADD INDEX idx_applications_list_created (user_id, deleted_at, archived_at, created_at),
+    ADD INDEX idx_applications_list_company (user_id, deleted_at, archived_at, company),
+    ADD INDEX idx_applications_list_status (user_id, deleted_at, archived_at, status_rank),
+    ADD INDEX idx_applications_list_next_event (user_id, deleted_at, archived_at, next_event_at);
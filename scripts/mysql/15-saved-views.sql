@@ -0,0 +1,20 @@
+USE internship_manager;
+
+-- 保存的申请列表视图
+CREATE TABLE IF NOT EXISTS saved_views (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    filter VARCHAR(1024),
+    search VARCHAR(256),
+    sort VARCHAR(16),
+    sort_desc TINYINT(1) NOT NULL DEFAULT 0,
+    archived TINYINT(1) NOT NULL DEFAULT 0,
+    pinned TINYINT(1) NOT NULL DEFAULT 0,
+    share_token VARCHAR(64) NULL,
+    UNIQUE INDEX idx_views_user_name (user_id, name),
+    UNIQUE INDEX idx_views_share_token (share_token),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
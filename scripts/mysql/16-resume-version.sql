@@ -0,0 +1,10 @@
+USE internship_manager;
+
+-- 投递时使用的简历版本
+ALTER TABLE applications
+    ADD COLUMN resume_version VARCHAR(64) NOT NULL DEFAULT '' AFTER notes,
+    ADD INDEX idx_applications_user_resume (user_id, resume_version);
+
+-- 漏斗分析按用户和动作读取状态历史
+ALTER TABLE application_histories
+    ADD INDEX idx_history_user_action (user_id, action, application_id);
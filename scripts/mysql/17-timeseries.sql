@@ -0,0 +1,9 @@
+USE internship_manager;
+
+-- 时间序列按目标状态和时间统计状态变更
+ALTER TABLE application_histories
+    ADD INDEX idx_history_user_to_status (user_id, action, to_status, created_at),
+    ADD INDEX idx_history_user_from_status (user_id, action, from_status, application_id);
+
+ALTER TABLE applications
+    ADD INDEX idx_applications_user_created (user_id, created_at);
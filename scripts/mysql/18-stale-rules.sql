@@ -0,0 +1,22 @@
+USE internship_manager;
+
+-- 长期无进展的申请可由系统自动改为 ghosted，状态排序加入新状态
+ALTER TABLE applications
+    MODIFY COLUMN status_rank TINYINT UNSIGNED
+        AS (FIELD(status, 'submitted', 'written', 'interview', 'accepted', 'rejected', 'ghosted')) STORED;
+
+-- 用户的停滞规则，每个用户一条，没有记录时使用默认规则（已投递超过30天，不自动处理）
+CREATE TABLE IF NOT EXISTS stale_rules (
+    user_id BIGINT UNSIGNED PRIMARY KEY,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    stale_days INT NOT NULL DEFAULT 30,
+    statuses VARCHAR(128) NOT NULL,
+    action VARCHAR(16) NOT NULL DEFAULT 'none',
+    action_days INT NOT NULL DEFAULT 0,
+    INDEX idx_stale_rules_action (action),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 停滞判断按状态和最后修改时间查询
+ALTER TABLE applications
+    ADD INDEX idx_applications_stale (user_id, status, updated_at);
@@ -0,0 +1,13 @@
+USE internship_manager;
+
+-- 周报订阅设置，每个用户一条，没有记录时不发送
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    user_id BIGINT UNSIGNED PRIMARY KEY,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    channel VARCHAR(16) NOT NULL DEFAULT 'none',
+    webhook_url VARCHAR(512),
+    timezone VARCHAR(64),
+    last_sent_week VARCHAR(10),
+    INDEX idx_digest_channel (channel),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
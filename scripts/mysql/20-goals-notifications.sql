@@ -0,0 +1,36 @@
+USE internship_manager;
+
+-- 周期目标，如“每周投递10份”
+CREATE TABLE IF NOT EXISTS goals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    title VARCHAR(64),
+    metric VARCHAR(32) NOT NULL,
+    period VARCHAR(16) NOT NULL,
+    target INT NOT NULL,
+    timezone VARCHAR(64),
+    active TINYINT(1) NOT NULL DEFAULT 1,
+    last_achieved_period VARCHAR(16),
+    INDEX idx_goals_user (user_id),
+    INDEX idx_goals_active (active),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 站内通知
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    title VARCHAR(128) NOT NULL,
+    body VARCHAR(1024),
+    read_at DATETIME NULL,
+    INDEX idx_notifications_user_read (user_id, read_at),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 目标按已完成日程的计划时间统计
+ALTER TABLE application_events
+    ADD INDEX idx_events_user_type_scheduled (user_id, type, scheduled_at);
@@ -0,0 +1,24 @@
+USE internship_manager;
+
+-- 待办任务，可关联到申请；自动创建的任务按 auto_key 去重
+CREATE TABLE IF NOT EXISTS tasks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    application_id BIGINT UNSIGNED NULL,
+    title VARCHAR(128) NOT NULL,
+    notes TEXT,
+    due_at DATETIME NULL,
+    completed TINYINT(1) NOT NULL DEFAULT 0,
+    completed_at DATETIME NULL,
+    source VARCHAR(16) NOT NULL DEFAULT 'user',
+    auto_key VARCHAR(64) NULL,
+    reminded_at DATETIME NULL,
+    INDEX idx_tasks_user_due (user_id, due_at),
+    INDEX idx_tasks_application (application_id),
+    INDEX idx_tasks_reminder (completed, reminded_at, due_at),
+    UNIQUE INDEX idx_tasks_user_auto_key (user_id, auto_key),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (application_id) REFERENCES applications(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,21 @@
+USE internship_manager;
+
+-- 面试题库：每道题来自一条申请，可关联到具体的面试日程；topics 为逗号分隔的分类
+CREATE TABLE IF NOT EXISTS interview_questions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    application_id BIGINT UNSIGNED NOT NULL,
+    event_id BIGINT UNSIGNED NULL,
+    question TEXT NOT NULL,
+    answer TEXT,
+    reflection TEXT,
+    topics VARCHAR(128) NOT NULL DEFAULT '',
+    INDEX idx_interview_questions_user (user_id),
+    INDEX idx_interview_questions_application (application_id),
+    INDEX idx_interview_questions_event (event_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (application_id) REFERENCES applications(id),
+    FOREIGN KEY (event_id) REFERENCES application_events(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
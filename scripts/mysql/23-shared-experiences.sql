@@ -0,0 +1,50 @@
+USE internship_manager;
+
+-- 匿名面经：user_id、application_id 只用于去重、撤回和账号清除，不在接口中返回
+CREATE TABLE IF NOT EXISTS experiences (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT UNSIGNED NOT NULL,
+    application_id BIGINT UNSIGNED NULL,
+    company VARCHAR(128) NOT NULL,
+    company_key VARCHAR(128) NOT NULL,
+    position VARCHAR(128) NOT NULL,
+    outcome VARCHAR(16) NOT NULL,
+    rounds JSON NOT NULL,
+    summary TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'published',
+    upvotes INT NOT NULL DEFAULT 0,
+    report_count INT NOT NULL DEFAULT 0,
+    INDEX idx_experiences_user (user_id),
+    UNIQUE INDEX idx_experiences_application (application_id),
+    INDEX idx_experiences_company_key (company_key),
+    INDEX idx_experiences_status (status),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (application_id) REFERENCES applications(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 面经点赞，每人每篇一次
+CREATE TABLE IF NOT EXISTS experience_votes (
+    experience_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (experience_id, user_id),
+    INDEX idx_experience_votes_user (user_id),
+    FOREIGN KEY (experience_id) REFERENCES experiences(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 面经举报，每人每篇一次，管理员处理后记录 resolved_at
+CREATE TABLE IF NOT EXISTS experience_reports (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    experience_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    reason VARCHAR(512) NOT NULL,
+    resolved_at DATETIME NULL,
+    UNIQUE INDEX idx_experience_reports_user (experience_id, user_id),
+    INDEX idx_experience_reports_reporter (user_id),
+    FOREIGN KEY (experience_id) REFERENCES experiences(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,9 @@
+USE internship_manager;
+
+-- 用户可以选择不参与公司统计
+ALTER TABLE users
+    ADD COLUMN insights_opt_out TINYINT(1) NOT NULL DEFAULT 0;
+
+-- 按公司汇总所有用户的申请
+ALTER TABLE applications
+    ADD INDEX idx_applications_company_key_user (company_key, user_id);
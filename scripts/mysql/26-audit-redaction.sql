@@ -0,0 +1,28 @@
+USE internship_manager;
+
+-- 账号清除后对审计日志中的个人信息做脱敏，记录本身保留
+ALTER TABLE audit_events
+    ADD COLUMN redacted_at DATETIME NULL AFTER request_id;
+
+-- 审计日志仍然只允许追加，唯一例外是脱敏：只能清空变更内容和 IP 并写入脱敏时间，其他字段不能改动
+DROP TRIGGER IF EXISTS audit_events_no_update;
+DELIMITER $$
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events
+FOR EACH ROW
+BEGIN
+    IF NOT (NEW.redacted_at IS NOT NULL
+        AND NEW.id = OLD.id
+        AND NEW.created_at <=> OLD.created_at
+        AND NEW.actor_id <=> OLD.actor_id
+        AND NEW.owner_id <=> OLD.owner_id
+        AND NEW.action <=> OLD.action
+        AND NEW.target_type <=> OLD.target_type
+        AND NEW.target_id <=> OLD.target_id
+        AND NEW.request_id <=> OLD.request_id
+        AND (NEW.before_data IS NULL OR NEW.before_data <=> OLD.before_data)
+        AND (NEW.after_data IS NULL OR NEW.after_data <=> OLD.after_data)
+        AND (NEW.ip = '' OR NEW.ip <=> OLD.ip)) THEN
+        SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_events is append-only';
+    END IF;
+END$$
+DELIMITER ;
@@ -0,0 +1,9 @@
+USE internship_manager;
+
+-- 导出下载令牌只保存 SHA-256 摘要，已有令牌原地转换，邮件中的链接继续有效
+ALTER TABLE export_jobs
+    CHANGE COLUMN download_token download_token_hash CHAR(64) NULL;
+
+UPDATE export_jobs
+SET download_token_hash = SHA2(download_token_hash, 256), updated_at = updated_at
+WHERE download_token_hash IS NOT NULL;
@@ -0,0 +1,7 @@
+USE internship_manager;
+
+-- 09 号迁移曾用申请的当前状态补写创建记录，已执行过的库在此更正为已投递。
+-- 系统来源的创建记录只可能来自该补写
+UPDATE application_histories
+SET to_status = 'submitted'
+WHERE action = 'create' AND source = 'system' AND to_status <> 'submitted';
@@ -0,0 +1,7 @@
+USE internship_manager;
+
+-- 公司名归一化规则调整（英文后缀只去除完整单词，如 Cisco 不再变成 cis），
+-- 清空已有的归一化结果，由后台任务 backfill-company-keys 按新规则重新计算（服务启动时即执行一次）。
+-- 只改这一列，更新时间保持不变
+UPDATE applications SET company_key = '', updated_at = updated_at;
+UPDATE experiences SET company_key = '', updated_at = updated_at;
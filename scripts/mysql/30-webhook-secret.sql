@@ -0,0 +1,10 @@
+USE internship_manager;
+
+-- webhook 签名密钥，每个订阅一个
+ALTER TABLE digest_subscriptions
+    ADD COLUMN webhook_secret VARCHAR(64) NOT NULL DEFAULT '' AFTER webhook_url;
+
+-- 已有的 webhook 订阅生成密钥，用户在订阅设置中查看
+UPDATE digest_subscriptions
+SET webhook_secret = SHA2(CONCAT(UUID(), RAND(), user_id), 256)
+WHERE channel = 'webhook' AND webhook_secret = '';
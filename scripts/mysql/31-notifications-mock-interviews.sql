@@ -0,0 +1,21 @@
+USE internship_manager;
+
+-- 通知推送方式，与周报订阅分开设置，每个用户一条，没有记录时只保存站内通知
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id BIGINT UNSIGNED PRIMARY KEY,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    channel VARCHAR(16) NOT NULL DEFAULT 'none',
+    webhook_url VARCHAR(512),
+    webhook_secret VARCHAR(64) NOT NULL DEFAULT '',
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 此前通知沿用周报订阅的推送方式，迁移时保留原有行为
+INSERT IGNORE INTO notification_settings (user_id, updated_at, channel, webhook_url, webhook_secret)
+SELECT user_id, updated_at, channel, webhook_url, webhook_secret
+FROM digest_subscriptions
+WHERE channel <> 'none';
+
+-- 模拟面试可以不关联申请
+ALTER TABLE application_events
+    MODIFY COLUMN application_id BIGINT UNSIGNED NULL;
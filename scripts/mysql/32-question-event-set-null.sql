@@ -0,0 +1,16 @@
+USE internship_manager;
+
+-- 删除日程时面试题保留，只取消关联：interview_questions.event_id 的外键改为 ON DELETE SET NULL。
+-- 原外键未命名，按 information_schema 查出名称后删除
+SET @fk = (SELECT CONSTRAINT_NAME FROM information_schema.KEY_COLUMN_USAGE
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'interview_questions'
+      AND COLUMN_NAME = 'event_id' AND REFERENCED_TABLE_NAME = 'application_events'
+    LIMIT 1);
+SET @sql = IF(@fk IS NULL, 'DO 0', CONCAT('ALTER TABLE interview_questions DROP FOREIGN KEY `', @fk, '`'));
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+ALTER TABLE interview_questions
+    ADD CONSTRAINT fk_interview_questions_event
+    FOREIGN KEY (event_id) REFERENCES application_events(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+USE internship_manager;
+
+-- 占用幂等键的请求，释放时只删除自己的占用
+ALTER TABLE idempotency_records
+    ADD COLUMN owner CHAR(32) NOT NULL DEFAULT '' AFTER request_hash;